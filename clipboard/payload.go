@@ -0,0 +1,127 @@
+// Package clipboard defines the types aretext uses to represent copied and
+// pasted text, including named clipboard pages for registers.
+package clipboard
+
+import "strings"
+
+// PageId identifies a clipboard page (register), such as the unnamed
+// register or one of the named registers "a" through "z".
+type PageId string
+
+// DefaultPage is the unnamed clipboard page used when no register is specified.
+const DefaultPage = PageId("")
+
+// Payload represents text received from a paste, along with a best-effort
+// guess at what kind of content it is. InsertFromBracketedPaste builds one
+// with NewPayload, which runs SniffMIMEType, so paste postprocessors can
+// treat a pasted diff differently from pasted prose. Bracketed paste into
+// the menu or search query, and OS clipboard pastes, don't build a Payload.
+type Payload struct {
+	// Text is the raw pasted content.
+	Text string
+
+	// MIMEType is a best-effort guess at the content type, such as
+	// "text/plain", "text/x-patch", "text/csv", or "text/x-<lang>".
+	MIMEType string
+
+	// SourceHint is a short human-readable description of why MIMEType was
+	// chosen, useful for debugging ("detected unified diff header").
+	SourceHint string
+}
+
+// NewPayload constructs a Payload from raw pasted text, sniffing its MIME type.
+func NewPayload(text string) Payload {
+	mimeType, hint := SniffMIMEType(text)
+	return Payload{
+		Text:       text,
+		MIMEType:   mimeType,
+		SourceHint: hint,
+	}
+}
+
+// SniffMIMEType guesses the MIME type of pasted text by looking for common
+// markers: a unified-diff header, a shebang or other language marker, or a
+// delimiter pattern consistent with CSV/TSV. It returns "text/plain" if
+// nothing more specific is detected.
+func SniffMIMEType(text string) (mimeType, hint string) {
+	if isUnifiedDiff(text) {
+		return "text/x-patch", "detected unified diff header"
+	}
+
+	if lang, ok := sniffLanguageMarker(text); ok {
+		return "text/x-" + lang, "detected language marker"
+	}
+
+	if isDelimited(text, '\t') {
+		return "text/tsv", "detected tab-separated rows"
+	}
+
+	if isDelimited(text, ',') {
+		return "text/csv", "detected comma-separated rows"
+	}
+
+	return "text/plain", ""
+}
+
+func isUnifiedDiff(text string) bool {
+	return strings.Contains(text, "\n--- a/") ||
+		strings.HasPrefix(text, "--- a/") ||
+		strings.Contains(text, "\n+++ b/") ||
+		strings.Contains(text, "\n@@ ") ||
+		strings.HasPrefix(text, "@@ ")
+}
+
+// languageMarkers maps a leading shebang or keyword fragment to a language tag.
+var languageMarkers = []struct {
+	prefix string
+	lang   string
+}{
+	{"#!/usr/bin/env python", "python"},
+	{"#!/usr/bin/python", "python"},
+	{"#!/usr/bin/env node", "javascript"},
+	{"#!/bin/bash", "shellscript"},
+	{"#!/bin/sh", "shellscript"},
+	{"package main", "go"},
+}
+
+func sniffLanguageMarker(text string) (string, bool) {
+	firstLine := text
+	if i := strings.IndexByte(text, '\n'); i >= 0 {
+		firstLine = text[:i]
+	}
+
+	for _, m := range languageMarkers {
+		if strings.HasPrefix(firstLine, m.prefix) {
+			return m.lang, true
+		}
+	}
+
+	return "", false
+}
+
+// isDelimited reports whether most non-empty lines in text have the same
+// number of occurrences of delim, which is a weak signal for delimited data.
+func isDelimited(text string, delim byte) bool {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if len(lines) < 2 {
+		return false
+	}
+
+	firstCount := -1
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+		count := strings.Count(line, string(delim))
+		if count == 0 {
+			return false
+		}
+		if firstCount == -1 {
+			firstCount = count
+		} else if count != firstCount {
+			return false
+		}
+	}
+
+	return firstCount > 0
+}
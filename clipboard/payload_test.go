@@ -0,0 +1,53 @@
+package clipboard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSniffMIMEType(t *testing.T) {
+	testCases := []struct {
+		name             string
+		text             string
+		expectedMIMEType string
+	}{
+		{
+			name:             "plain text",
+			text:             "just some words",
+			expectedMIMEType: "text/plain",
+		},
+		{
+			name:             "unified diff",
+			text:             "--- a/foo.go\n+++ b/foo.go\n@@ -1,2 +1,2 @@\n-old\n+new\n",
+			expectedMIMEType: "text/x-patch",
+		},
+		{
+			name:             "python shebang",
+			text:             "#!/usr/bin/env python\nprint('hi')\n",
+			expectedMIMEType: "text/x-python",
+		},
+		{
+			name:             "go source",
+			text:             "package main\n\nfunc main() {}\n",
+			expectedMIMEType: "text/x-go",
+		},
+		{
+			name:             "csv",
+			text:             "a,b,c\n1,2,3\n4,5,6\n",
+			expectedMIMEType: "text/csv",
+		},
+		{
+			name:             "tsv",
+			text:             "a\tb\tc\n1\t2\t3\n",
+			expectedMIMEType: "text/tsv",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mimeType, _ := SniffMIMEType(tc.text)
+			assert.Equal(t, tc.expectedMIMEType, mimeType)
+		})
+	}
+}
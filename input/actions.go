@@ -90,6 +90,30 @@ func CursorNextWordEnd(count uint64, withPunctuation bool) Action {
 	}
 }
 
+func CursorNextSubwordStart(count uint64) Action {
+	return func(s *state.EditorState) {
+		state.MoveCursor(s, func(params state.LocatorParams) uint64 {
+			return locate.NextSubwordStart(params.TextTree, params.CursorPos, count)
+		})
+	}
+}
+
+func CursorPrevSubwordStart(count uint64) Action {
+	return func(s *state.EditorState) {
+		state.MoveCursor(s, func(params state.LocatorParams) uint64 {
+			return locate.PrevSubwordStart(params.TextTree, params.CursorPos, count)
+		})
+	}
+}
+
+func CursorNextSubwordEnd(count uint64) Action {
+	return func(s *state.EditorState) {
+		state.MoveCursor(s, func(params state.LocatorParams) uint64 {
+			return locate.NextSubwordEnd(params.TextTree, params.CursorPos, count)
+		})
+	}
+}
+
 func CursorPrevParagraph(s *state.EditorState) {
 	state.MoveCursor(s, func(params state.LocatorParams) uint64 {
 		return locate.PrevParagraph(params.TextTree, params.CursorPos)
@@ -573,6 +597,28 @@ func DeleteInnerWord(count uint64, clipboardPage clipboard.PageId) Action {
 	}
 }
 
+func DeleteASubword(count uint64, clipboardPage clipboard.PageId) Action {
+	return func(s *state.EditorState) {
+		state.DeleteRange(s, func(params state.LocatorParams) (uint64, uint64) {
+			return locate.SubwordObject(params.TextTree, params.CursorPos, count)
+		}, clipboardPage)
+		state.MoveCursor(s, func(params state.LocatorParams) uint64 {
+			return locate.ClosestCharOnLine(params.TextTree, params.CursorPos)
+		})
+	}
+}
+
+func DeleteInnerSubword(count uint64, clipboardPage clipboard.PageId) Action {
+	return func(s *state.EditorState) {
+		state.DeleteRange(s, func(params state.LocatorParams) (uint64, uint64) {
+			return locate.InnerSubwordObject(params.TextTree, params.CursorPos, count)
+		}, clipboardPage)
+		state.MoveCursor(s, func(params state.LocatorParams) uint64 {
+			return locate.ClosestCharOnLine(params.TextTree, params.CursorPos)
+		})
+	}
+}
+
 func DeleteStringObject(quoteRune rune, includeQuotes bool, clipboardPage clipboard.PageId) Action {
 	return func(s *state.EditorState) {
 		state.DeleteRange(s, func(params state.LocatorParams) (uint64, uint64) {
@@ -614,6 +660,24 @@ func ChangeInnerWord(count uint64, clipboardPage clipboard.PageId) Action {
 	}
 }
 
+func ChangeASubword(count uint64, clipboardPage clipboard.PageId) Action {
+	return func(s *state.EditorState) {
+		state.DeleteRange(s, func(params state.LocatorParams) (uint64, uint64) {
+			return locate.SubwordObject(params.TextTree, params.CursorPos, count)
+		}, clipboardPage)
+		EnterInsertMode(s)
+	}
+}
+
+func ChangeInnerSubword(count uint64, clipboardPage clipboard.PageId) Action {
+	return func(s *state.EditorState) {
+		state.DeleteRange(s, func(params state.LocatorParams) (uint64, uint64) {
+			return locate.InnerSubwordObject(params.TextTree, params.CursorPos, count)
+		}, clipboardPage)
+		EnterInsertMode(s)
+	}
+}
+
 func ChangeStringObject(quoteRune rune, includeQuotes bool, clipboardPage clipboard.PageId) Action {
 	return func(s *state.EditorState) {
 		state.DeleteRange(s, func(params state.LocatorParams) (uint64, uint64) {
@@ -693,6 +757,22 @@ func CopyInnerWord(count uint64, clipboardPage clipboard.PageId) Action {
 	}
 }
 
+func CopyASubword(count uint64, clipboardPage clipboard.PageId) Action {
+	return func(s *state.EditorState) {
+		state.CopyRange(s, clipboardPage, func(params state.LocatorParams) (uint64, uint64) {
+			return locate.SubwordObject(params.TextTree, params.CursorPos, count)
+		})
+	}
+}
+
+func CopyInnerSubword(count uint64, clipboardPage clipboard.PageId) Action {
+	return func(s *state.EditorState) {
+		state.CopyRange(s, clipboardPage, func(params state.LocatorParams) (uint64, uint64) {
+			return locate.InnerSubwordObject(params.TextTree, params.CursorPos, count)
+		})
+	}
+}
+
 func CopyStringObject(quoteRune rune, includeQuotes bool, clipboardPage clipboard.PageId) Action {
 	return func(s *state.EditorState) {
 		state.CopyRange(s, clipboardPage, func(params state.LocatorParams) (uint64, uint64) {
@@ -754,7 +834,9 @@ func PasteBeforeCursor(clipboardPage clipboard.PageId) Action {
 func InsertFromBracketedPaste(text string) Action {
 	return func(s *state.EditorState) {
 		wrappedAction := func(s *state.EditorState) {
-			state.InsertText(s, text)
+			payload := clipboard.NewPayload(text)
+			processedText := state.ApplyPastePostprocessor(payload)
+			state.InsertText(s, processedText)
 			state.ScrollViewToCursor(s)
 		}
 		wrappedAction(s)
@@ -973,6 +1055,30 @@ func ToggleCaseInSelectionAndReturnToNormalMode(selectionEndLoc state.Locator) A
 	}
 }
 
+func UppercaseSelectionAndReturnToNormalMode(selectionEndLoc state.Locator) Action {
+	return func(s *state.EditorState) {
+		state.MoveCursorToStartOfSelection(s)
+		state.UppercaseSelection(s, selectionEndLoc)
+		ReturnToNormalMode(s)
+	}
+}
+
+func LowercaseSelectionAndReturnToNormalMode(selectionEndLoc state.Locator) Action {
+	return func(s *state.EditorState) {
+		state.MoveCursorToStartOfSelection(s)
+		state.LowercaseSelection(s, selectionEndLoc)
+		ReturnToNormalMode(s)
+	}
+}
+
+func TitlecaseSelectionAndReturnToNormalMode(selectionEndLoc state.Locator) Action {
+	return func(s *state.EditorState) {
+		state.MoveCursorToStartOfSelection(s)
+		state.TitlecaseSelection(s, selectionEndLoc)
+		ReturnToNormalMode(s)
+	}
+}
+
 func IndentSelectionAndReturnToNormalMode(selectionEndLoc state.Locator, count uint64) Action {
 	return func(s *state.EditorState) {
 		state.MoveCursorToStartOfSelection(s)
@@ -1057,3 +1163,233 @@ func ReplayLastActionMacro(count uint64) Action {
 		state.ReplayLastActionMacro(s, count)
 	}
 }
+
+// ensureVisualMode enters charwise or linewise visual mode if the editor
+// isn't already in visual mode, without disturbing an existing selection.
+// The SelectMove* actions use this so a "shift+motion" keymap layered on top
+// of aretext's modal core can extend a selection with repeated motions
+// instead of toggling visual mode on and off with every keystroke.
+func ensureVisualMode(s *state.EditorState, mode selection.Mode) {
+	if s.InputMode() != state.InputModeVisual {
+		state.ToggleVisualMode(s, mode)
+	}
+}
+
+func SelectMoveWordRight(count uint64, withPunctuation bool) Action {
+	return func(s *state.EditorState) {
+		ensureVisualMode(s, selection.ModeChar)
+		state.MoveCursor(s, func(params state.LocatorParams) uint64 {
+			return locate.NextWordStart(params.TextTree, params.CursorPos, count, withPunctuation, false, true)
+		})
+	}
+}
+
+func SelectMoveWordLeft(count uint64, withPunctuation bool) Action {
+	return func(s *state.EditorState) {
+		ensureVisualMode(s, selection.ModeChar)
+		state.MoveCursor(s, func(params state.LocatorParams) uint64 {
+			return locate.PrevWordStart(params.TextTree, params.CursorPos, count, withPunctuation)
+		})
+	}
+}
+
+func SelectMoveDown(count uint64) Action {
+	return func(s *state.EditorState) {
+		ensureVisualMode(s, selection.ModeChar)
+		state.MoveCursorToLineBelow(s, count)
+	}
+}
+
+func SelectMoveUp(count uint64) Action {
+	return func(s *state.EditorState) {
+		ensureVisualMode(s, selection.ModeChar)
+		state.MoveCursorToLineAbove(s, count)
+	}
+}
+
+func SelectMoveToStartOfLine(s *state.EditorState) {
+	ensureVisualMode(s, selection.ModeChar)
+	state.MoveCursor(s, func(params state.LocatorParams) uint64 {
+		return locate.PrevLineBoundary(params.TextTree, params.CursorPos)
+	})
+}
+
+func SelectMoveToEndOfLine(s *state.EditorState) {
+	ensureVisualMode(s, selection.ModeChar)
+	state.MoveCursor(s, func(params state.LocatorParams) uint64 {
+		return locate.NextLineBoundary(params.TextTree, true, params.CursorPos)
+	})
+}
+
+func SelectMoveToMatchingDelimiter(s *state.EditorState) {
+	ensureVisualMode(s, selection.ModeChar)
+	state.MoveCursor(s, func(params state.LocatorParams) uint64 {
+		matchPos, hasMatch := locate.MatchingCodeBlockDelimiter(params.TextTree, params.SyntaxParser, params.CursorPos)
+		if hasMatch {
+			return matchPos
+		}
+		return params.CursorPos
+	})
+}
+
+func SelectMoveToSearchMatch(direction state.SearchDirection, count uint64) Action {
+	return func(s *state.EditorState) {
+		ensureVisualMode(s, selection.ModeChar)
+		state.SearchWordUnderCursor(s, direction, state.SearchCompleteMoveCursorToMatch, count)
+	}
+}
+
+func SelectInnerParagraph(count uint64) Action {
+	return func(s *state.EditorState) {
+		state.SelectRange(s, func(params state.LocatorParams) (uint64, uint64) {
+			return locate.InnerParagraphObject(params.TextTree, params.CursorPos, count)
+		})
+	}
+}
+
+func SelectAParagraph(count uint64) Action {
+	return func(s *state.EditorState) {
+		state.SelectRange(s, func(params state.LocatorParams) (uint64, uint64) {
+			return locate.ParagraphObject(params.TextTree, params.CursorPos, count)
+		})
+	}
+}
+
+func SelectInnerSentence(count uint64) Action {
+	return func(s *state.EditorState) {
+		state.SelectRange(s, func(params state.LocatorParams) (uint64, uint64) {
+			return locate.InnerSentenceObject(params.TextTree, params.CursorPos, count)
+		})
+	}
+}
+
+func SelectASentence(count uint64) Action {
+	return func(s *state.EditorState) {
+		state.SelectRange(s, func(params state.LocatorParams) (uint64, uint64) {
+			return locate.ASentenceObject(params.TextTree, params.CursorPos, count)
+		})
+	}
+}
+
+func DeleteInnerParagraph(count uint64, clipboardPage clipboard.PageId) Action {
+	return func(s *state.EditorState) {
+		state.DeleteRange(s, func(params state.LocatorParams) (uint64, uint64) {
+			return locate.InnerParagraphObject(params.TextTree, params.CursorPos, count)
+		}, clipboardPage)
+	}
+}
+
+func DeleteAParagraph(count uint64, clipboardPage clipboard.PageId) Action {
+	return func(s *state.EditorState) {
+		state.DeleteRange(s, func(params state.LocatorParams) (uint64, uint64) {
+			return locate.ParagraphObject(params.TextTree, params.CursorPos, count)
+		}, clipboardPage)
+	}
+}
+
+func DeleteInnerSentence(count uint64, clipboardPage clipboard.PageId) Action {
+	return func(s *state.EditorState) {
+		state.DeleteRange(s, func(params state.LocatorParams) (uint64, uint64) {
+			return locate.InnerSentenceObject(params.TextTree, params.CursorPos, count)
+		}, clipboardPage)
+	}
+}
+
+func DeleteASentence(count uint64, clipboardPage clipboard.PageId) Action {
+	return func(s *state.EditorState) {
+		state.DeleteRange(s, func(params state.LocatorParams) (uint64, uint64) {
+			return locate.ASentenceObject(params.TextTree, params.CursorPos, count)
+		}, clipboardPage)
+	}
+}
+
+func ChangeInnerParagraph(count uint64, clipboardPage clipboard.PageId) Action {
+	return func(s *state.EditorState) {
+		state.DeleteRange(s, func(params state.LocatorParams) (uint64, uint64) {
+			return locate.InnerParagraphObject(params.TextTree, params.CursorPos, count)
+		}, clipboardPage)
+		EnterInsertMode(s)
+	}
+}
+
+func ChangeInnerSentence(count uint64, clipboardPage clipboard.PageId) Action {
+	return func(s *state.EditorState) {
+		state.DeleteRange(s, func(params state.LocatorParams) (uint64, uint64) {
+			return locate.InnerSentenceObject(params.TextTree, params.CursorPos, count)
+		}, clipboardPage)
+		EnterInsertMode(s)
+	}
+}
+
+func CopyInnerParagraph(count uint64, clipboardPage clipboard.PageId) Action {
+	return func(s *state.EditorState) {
+		state.CopyRange(s, clipboardPage, func(params state.LocatorParams) (uint64, uint64) {
+			return locate.InnerParagraphObject(params.TextTree, params.CursorPos, count)
+		})
+	}
+}
+
+func CopyInnerSentence(count uint64, clipboardPage clipboard.PageId) Action {
+	return func(s *state.EditorState) {
+		state.CopyRange(s, clipboardPage, func(params state.LocatorParams) (uint64, uint64) {
+			return locate.InnerSentenceObject(params.TextTree, params.CursorPos, count)
+		})
+	}
+}
+
+// UppercaseWord implements "gUw": uppercase from the cursor to the start of
+// the next word, without moving the cursor.
+func UppercaseWord(count uint64, withPunctuation bool) Action {
+	return func(s *state.EditorState) {
+		startPos := s.DocumentBuffer().CursorPosition()
+		state.UppercaseSelection(s, func(params state.LocatorParams) uint64 {
+			return locate.NextWordStart(params.TextTree, params.CursorPos, count, withPunctuation, true, false)
+		})
+		state.MoveCursor(s, func(params state.LocatorParams) uint64 {
+			return startPos
+		})
+	}
+}
+
+// UppercaseToEndOfLine implements "gU$": uppercase from the cursor to the end of the line.
+func UppercaseToEndOfLine(s *state.EditorState) {
+	startPos := s.DocumentBuffer().CursorPosition()
+	state.UppercaseSelection(s, func(params state.LocatorParams) uint64 {
+		return locate.NextLineBoundary(params.TextTree, true, params.CursorPos)
+	})
+	state.MoveCursor(s, func(params state.LocatorParams) uint64 {
+		return startPos
+	})
+}
+
+// LowercaseWord implements "guw": lowercase from the cursor to the start of
+// the next word, without moving the cursor.
+func LowercaseWord(count uint64, withPunctuation bool) Action {
+	return func(s *state.EditorState) {
+		startPos := s.DocumentBuffer().CursorPosition()
+		state.LowercaseSelection(s, func(params state.LocatorParams) uint64 {
+			return locate.NextWordStart(params.TextTree, params.CursorPos, count, withPunctuation, true, false)
+		})
+		state.MoveCursor(s, func(params state.LocatorParams) uint64 {
+			return startPos
+		})
+	}
+}
+
+// ToggleCaseInnerParagraph implements "g~ip": toggle the case of the paragraph under the cursor.
+func ToggleCaseInnerParagraph(count uint64) Action {
+	return func(s *state.EditorState) {
+		startPos := s.DocumentBuffer().CursorPosition()
+		state.MoveCursor(s, func(params state.LocatorParams) uint64 {
+			paragraphStartPos, _ := locate.InnerParagraphObject(params.TextTree, params.CursorPos, count)
+			return paragraphStartPos
+		})
+		state.ToggleCaseInSelection(s, func(params state.LocatorParams) uint64 {
+			_, endPos := locate.InnerParagraphObject(params.TextTree, params.CursorPos, count)
+			return endPos
+		})
+		state.MoveCursor(s, func(params state.LocatorParams) uint64 {
+			return startPos
+		})
+	}
+}
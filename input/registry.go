@@ -0,0 +1,243 @@
+package input
+
+import (
+	"fmt"
+
+	"github.com/aretext/aretext/clipboard"
+	"github.com/aretext/aretext/state"
+)
+
+// ActionArgs holds the parameters that a named action factory can use to
+// construct an Action. Not every field applies to every action; a factory
+// reads only the fields it needs and ignores the rest.
+type ActionArgs struct {
+	Count           uint64
+	ClipboardPage   clipboard.PageId
+	WithPunctuation bool
+	IncludeQuotes   bool
+	Char            rune
+	IncludeChar     bool
+	Direction       state.SearchDirection
+}
+
+// ActionFactory constructs an Action from user-supplied arguments.
+// This is the indirection that lets a config file bind a key sequence to an
+// action by name instead of by Go identifier.
+type ActionFactory func(args ActionArgs) Action
+
+// actionRegistry maps a stable action name to the factory that constructs it.
+var actionRegistry = make(map[string]ActionFactory)
+
+// RegisterAction associates a stable name with a factory for constructing the
+// corresponding Action. Config loaders use this registry to translate a
+// binding like `{key: "gw", action: "CursorNextWordStart", args: {count: 2}}`
+// into a real Action without recompiling aretext.
+//
+// RegisterAction panics if name is already registered, since that indicates
+// a programming error (two built-ins or plugins claiming the same name)
+// rather than a condition a caller should recover from.
+func RegisterAction(name string, factory ActionFactory) {
+	if _, ok := actionRegistry[name]; ok {
+		panic(fmt.Sprintf("action %q is already registered", name))
+	}
+	actionRegistry[name] = factory
+}
+
+// LookupAction resolves a registered action name to an Action, applying args.
+// The second return value is false if no action is registered under name.
+func LookupAction(name string, args ActionArgs) (Action, bool) {
+	factory, ok := actionRegistry[name]
+	if !ok {
+		return nil, false
+	}
+	return factory(args), true
+}
+
+func init() {
+	RegisterAction("CursorLeft", func(args ActionArgs) Action {
+		return CursorLeft(args.Count)
+	})
+	RegisterAction("CursorBack", func(args ActionArgs) Action {
+		return CursorBack(args.Count)
+	})
+	RegisterAction("CursorRight", func(args ActionArgs) Action {
+		return CursorRight(args.Count)
+	})
+	RegisterAction("CursorRightIncludeEndOfLineOrFile", func(args ActionArgs) Action {
+		return CursorRightIncludeEndOfLineOrFile
+	})
+	RegisterAction("CursorUp", func(args ActionArgs) Action {
+		return CursorUp(args.Count)
+	})
+	RegisterAction("CursorDown", func(args ActionArgs) Action {
+		return CursorDown(args.Count)
+	})
+	RegisterAction("CursorNextLine", func(args ActionArgs) Action {
+		return CursorNextLine(args.Count)
+	})
+	RegisterAction("CursorNextWordStart", func(args ActionArgs) Action {
+		return CursorNextWordStart(args.Count, args.WithPunctuation)
+	})
+	RegisterAction("CursorPrevWordStart", func(args ActionArgs) Action {
+		return CursorPrevWordStart(args.Count, args.WithPunctuation)
+	})
+	RegisterAction("CursorNextWordEnd", func(args ActionArgs) Action {
+		return CursorNextWordEnd(args.Count, args.WithPunctuation)
+	})
+	RegisterAction("CursorNextSubwordStart", func(args ActionArgs) Action {
+		return CursorNextSubwordStart(args.Count)
+	})
+	RegisterAction("CursorPrevSubwordStart", func(args ActionArgs) Action {
+		return CursorPrevSubwordStart(args.Count)
+	})
+	RegisterAction("CursorNextSubwordEnd", func(args ActionArgs) Action {
+		return CursorNextSubwordEnd(args.Count)
+	})
+	RegisterAction("CursorPrevParagraph", func(args ActionArgs) Action {
+		return CursorPrevParagraph
+	})
+	RegisterAction("CursorNextParagraph", func(args ActionArgs) Action {
+		return CursorNextParagraph
+	})
+	RegisterAction("CursorToNextMatchingChar", func(args ActionArgs) Action {
+		return CursorToNextMatchingChar(args.Char, args.Count, args.IncludeChar)
+	})
+	RegisterAction("CursorToPrevMatchingChar", func(args ActionArgs) Action {
+		return CursorToPrevMatchingChar(args.Char, args.Count, args.IncludeChar)
+	})
+	RegisterAction("CursorLineStart", func(args ActionArgs) Action {
+		return CursorLineStart
+	})
+	RegisterAction("CursorLineStartNonWhitespace", func(args ActionArgs) Action {
+		return CursorLineStartNonWhitespace
+	})
+	RegisterAction("CursorLineEnd", func(args ActionArgs) Action {
+		return CursorLineEnd
+	})
+	RegisterAction("CursorLineEndIncludeEndOfLineOrFile", func(args ActionArgs) Action {
+		return CursorLineEndIncludeEndOfLineOrFile
+	})
+	RegisterAction("CursorStartOfLineNum", func(args ActionArgs) Action {
+		return CursorStartOfLineNum(args.Count)
+	})
+	RegisterAction("CursorStartOfLastLine", func(args ActionArgs) Action {
+		return CursorStartOfLastLine
+	})
+	RegisterAction("CursorMatchingCodeBlockDelimiter", func(args ActionArgs) Action {
+		return CursorMatchingCodeBlockDelimiter
+	})
+	RegisterAction("DeleteParenBlock", func(args ActionArgs) Action {
+		return DeleteParenBlock(args.IncludeQuotes, args.ClipboardPage)
+	})
+	RegisterAction("DeleteBraceBlock", func(args ActionArgs) Action {
+		return DeleteBraceBlock(args.IncludeQuotes, args.ClipboardPage)
+	})
+	RegisterAction("DeleteAngleBlock", func(args ActionArgs) Action {
+		return DeleteAngleBlock(args.IncludeQuotes, args.ClipboardPage)
+	})
+	RegisterAction("DeletePrevChar", func(args ActionArgs) Action {
+		return DeletePrevChar(args.ClipboardPage)
+	})
+	RegisterAction("DeletePrevCharInLine", func(args ActionArgs) Action {
+		return DeletePrevCharInLine(args.ClipboardPage)
+	})
+	RegisterAction("DeleteNextCharInLine", func(args ActionArgs) Action {
+		return DeleteNextCharInLine(args.Count, args.ClipboardPage)
+	})
+	RegisterAction("DeleteLines", func(args ActionArgs) Action {
+		return DeleteLines(args.Count, args.ClipboardPage)
+	})
+	RegisterAction("DeleteDown", func(args ActionArgs) Action {
+		return DeleteDown(args.ClipboardPage)
+	})
+	RegisterAction("DeleteUp", func(args ActionArgs) Action {
+		return DeleteUp(args.ClipboardPage)
+	})
+	RegisterAction("DeleteToEndOfLine", func(args ActionArgs) Action {
+		return DeleteToEndOfLine(args.ClipboardPage)
+	})
+	RegisterAction("DeleteToStartOfLine", func(args ActionArgs) Action {
+		return DeleteToStartOfLine(args.ClipboardPage)
+	})
+	RegisterAction("DeleteToStartOfLineNonWhitespace", func(args ActionArgs) Action {
+		return DeleteToStartOfLineNonWhitespace(args.ClipboardPage)
+	})
+	RegisterAction("DeleteToStartOfNextWord", func(args ActionArgs) Action {
+		return DeleteToStartOfNextWord(args.Count, args.ClipboardPage, args.WithPunctuation)
+	})
+	RegisterAction("DeleteAWord", func(args ActionArgs) Action {
+		return DeleteAWord(args.Count, args.ClipboardPage)
+	})
+	RegisterAction("DeleteInnerWord", func(args ActionArgs) Action {
+		return DeleteInnerWord(args.Count, args.ClipboardPage)
+	})
+	RegisterAction("DeleteASubword", func(args ActionArgs) Action {
+		return DeleteASubword(args.Count, args.ClipboardPage)
+	})
+	RegisterAction("DeleteInnerSubword", func(args ActionArgs) Action {
+		return DeleteInnerSubword(args.Count, args.ClipboardPage)
+	})
+	RegisterAction("DeleteStringObject", func(args ActionArgs) Action {
+		return DeleteStringObject(args.Char, args.IncludeQuotes, args.ClipboardPage)
+	})
+	RegisterAction("ChangeWord", func(args ActionArgs) Action {
+		return ChangeWord(args.Count, args.ClipboardPage)
+	})
+	RegisterAction("ChangeAWord", func(args ActionArgs) Action {
+		return ChangeAWord(args.Count, args.ClipboardPage)
+	})
+	RegisterAction("ChangeInnerWord", func(args ActionArgs) Action {
+		return ChangeInnerWord(args.Count, args.ClipboardPage)
+	})
+	RegisterAction("ChangeASubword", func(args ActionArgs) Action {
+		return ChangeASubword(args.Count, args.ClipboardPage)
+	})
+	RegisterAction("ChangeInnerSubword", func(args ActionArgs) Action {
+		return ChangeInnerSubword(args.Count, args.ClipboardPage)
+	})
+	RegisterAction("ChangeStringObject", func(args ActionArgs) Action {
+		return ChangeStringObject(args.Char, args.IncludeQuotes, args.ClipboardPage)
+	})
+	RegisterAction("ReplaceCharacter", func(args ActionArgs) Action {
+		return ReplaceCharacter(args.Char)
+	})
+	RegisterAction("ToggleCaseAtCursor", func(args ActionArgs) Action {
+		return ToggleCaseAtCursor
+	})
+	RegisterAction("IndentLine", func(args ActionArgs) Action {
+		return IndentLine(args.Count)
+	})
+	RegisterAction("OutdentLine", func(args ActionArgs) Action {
+		return OutdentLine(args.Count)
+	})
+	RegisterAction("CopyAWord", func(args ActionArgs) Action {
+		return CopyAWord(args.Count, args.ClipboardPage)
+	})
+	RegisterAction("CopyInnerWord", func(args ActionArgs) Action {
+		return CopyInnerWord(args.Count, args.ClipboardPage)
+	})
+	RegisterAction("CopyASubword", func(args ActionArgs) Action {
+		return CopyASubword(args.Count, args.ClipboardPage)
+	})
+	RegisterAction("CopyInnerSubword", func(args ActionArgs) Action {
+		return CopyInnerSubword(args.Count, args.ClipboardPage)
+	})
+	RegisterAction("CopyStringObject", func(args ActionArgs) Action {
+		return CopyStringObject(args.Char, args.IncludeQuotes, args.ClipboardPage)
+	})
+	RegisterAction("CopyLines", func(args ActionArgs) Action {
+		return CopyLines(args.ClipboardPage)
+	})
+	RegisterAction("PasteAfterCursor", func(args ActionArgs) Action {
+		return PasteAfterCursor(args.ClipboardPage)
+	})
+	RegisterAction("PasteBeforeCursor", func(args ActionArgs) Action {
+		return PasteBeforeCursor(args.ClipboardPage)
+	})
+	RegisterAction("Undo", func(args ActionArgs) Action {
+		return Undo
+	})
+	RegisterAction("Redo", func(args ActionArgs) Action {
+		return Redo
+	})
+}
@@ -0,0 +1,137 @@
+package input
+
+// This file extends the action registry from registry.go with the
+// selection, search, visual-mode, and paste actions so that, like the
+// cursor/delete/change actions, they can be bound from config by name
+// instead of Go identifier.
+func init() {
+	RegisterAction("SelectInnerWord", func(args ActionArgs) Action {
+		return SelectInnerWord(args.Count)
+	})
+	RegisterAction("SelectAWord", func(args ActionArgs) Action {
+		return SelectAWord(args.Count)
+	})
+	RegisterAction("SelectStringObject", func(args ActionArgs) Action {
+		return SelectStringObject(args.Char, args.IncludeQuotes)
+	})
+	RegisterAction("SelectParenBlock", func(args ActionArgs) Action {
+		return SelectParenBlock(args.IncludeQuotes)
+	})
+	RegisterAction("SelectBraceBlock", func(args ActionArgs) Action {
+		return SelectBraceBlock(args.IncludeQuotes)
+	})
+	RegisterAction("SelectAngleBlock", func(args ActionArgs) Action {
+		return SelectAngleBlock(args.IncludeQuotes)
+	})
+	RegisterAction("ToggleVisualModeCharwise", func(args ActionArgs) Action {
+		return ToggleVisualModeCharwise
+	})
+	RegisterAction("ToggleVisualModeLinewise", func(args ActionArgs) Action {
+		return ToggleVisualModeLinewise
+	})
+	RegisterAction("StartSearch", func(args ActionArgs) Action {
+		return StartSearch(args.Direction)
+	})
+	RegisterAction("StartSearchForDelete", func(args ActionArgs) Action {
+		return StartSearchForDelete(args.Direction, args.ClipboardPage)
+	})
+	RegisterAction("StartSearchForChange", func(args ActionArgs) Action {
+		return StartSearchForChange(args.Direction, args.ClipboardPage)
+	})
+	RegisterAction("StartSearchForCopy", func(args ActionArgs) Action {
+		return StartSearchForCopy(args.Direction, args.ClipboardPage)
+	})
+	RegisterAction("FindNextMatch", func(args ActionArgs) Action {
+		return FindNextMatch
+	})
+	RegisterAction("FindPrevMatch", func(args ActionArgs) Action {
+		return FindPrevMatch
+	})
+	RegisterAction("SearchWordUnderCursor", func(args ActionArgs) Action {
+		return SearchWordUnderCursor(args.Direction, args.Count)
+	})
+	RegisterAction("CopyToStartOfNextWord", func(args ActionArgs) Action {
+		return CopyToStartOfNextWord(args.Count, args.ClipboardPage, args.WithPunctuation)
+	})
+	RegisterAction("CopyToNextMatchingChar", func(args ActionArgs) Action {
+		return CopyToNextMatchingChar(args.Char, args.Count, args.ClipboardPage, args.IncludeChar)
+	})
+	RegisterAction("CopyToPrevMatchingChar", func(args ActionArgs) Action {
+		return CopyToPrevMatchingChar(args.Char, args.Count, args.ClipboardPage, args.IncludeChar)
+	})
+	RegisterAction("ReplayLastActionMacro", func(args ActionArgs) Action {
+		return ReplayLastActionMacro(args.Count)
+	})
+	RegisterAction("SelectMoveWordRight", func(args ActionArgs) Action {
+		return SelectMoveWordRight(args.Count, args.WithPunctuation)
+	})
+	RegisterAction("SelectMoveWordLeft", func(args ActionArgs) Action {
+		return SelectMoveWordLeft(args.Count, args.WithPunctuation)
+	})
+	RegisterAction("SelectMoveDown", func(args ActionArgs) Action {
+		return SelectMoveDown(args.Count)
+	})
+	RegisterAction("SelectMoveUp", func(args ActionArgs) Action {
+		return SelectMoveUp(args.Count)
+	})
+	RegisterAction("SelectMoveToStartOfLine", func(args ActionArgs) Action {
+		return SelectMoveToStartOfLine
+	})
+	RegisterAction("SelectMoveToEndOfLine", func(args ActionArgs) Action {
+		return SelectMoveToEndOfLine
+	})
+	RegisterAction("SelectMoveToMatchingDelimiter", func(args ActionArgs) Action {
+		return SelectMoveToMatchingDelimiter
+	})
+	RegisterAction("SelectMoveToSearchMatch", func(args ActionArgs) Action {
+		return SelectMoveToSearchMatch(args.Direction, args.Count)
+	})
+	RegisterAction("SelectInnerParagraph", func(args ActionArgs) Action {
+		return SelectInnerParagraph(args.Count)
+	})
+	RegisterAction("SelectAParagraph", func(args ActionArgs) Action {
+		return SelectAParagraph(args.Count)
+	})
+	RegisterAction("SelectInnerSentence", func(args ActionArgs) Action {
+		return SelectInnerSentence(args.Count)
+	})
+	RegisterAction("SelectASentence", func(args ActionArgs) Action {
+		return SelectASentence(args.Count)
+	})
+	RegisterAction("DeleteInnerParagraph", func(args ActionArgs) Action {
+		return DeleteInnerParagraph(args.Count, args.ClipboardPage)
+	})
+	RegisterAction("DeleteAParagraph", func(args ActionArgs) Action {
+		return DeleteAParagraph(args.Count, args.ClipboardPage)
+	})
+	RegisterAction("DeleteInnerSentence", func(args ActionArgs) Action {
+		return DeleteInnerSentence(args.Count, args.ClipboardPage)
+	})
+	RegisterAction("DeleteASentence", func(args ActionArgs) Action {
+		return DeleteASentence(args.Count, args.ClipboardPage)
+	})
+	RegisterAction("ChangeInnerParagraph", func(args ActionArgs) Action {
+		return ChangeInnerParagraph(args.Count, args.ClipboardPage)
+	})
+	RegisterAction("ChangeInnerSentence", func(args ActionArgs) Action {
+		return ChangeInnerSentence(args.Count, args.ClipboardPage)
+	})
+	RegisterAction("CopyInnerParagraph", func(args ActionArgs) Action {
+		return CopyInnerParagraph(args.Count, args.ClipboardPage)
+	})
+	RegisterAction("CopyInnerSentence", func(args ActionArgs) Action {
+		return CopyInnerSentence(args.Count, args.ClipboardPage)
+	})
+	RegisterAction("UppercaseWord", func(args ActionArgs) Action {
+		return UppercaseWord(args.Count, args.WithPunctuation)
+	})
+	RegisterAction("UppercaseToEndOfLine", func(args ActionArgs) Action {
+		return UppercaseToEndOfLine
+	})
+	RegisterAction("LowercaseWord", func(args ActionArgs) Action {
+		return LowercaseWord(args.Count, args.WithPunctuation)
+	})
+	RegisterAction("ToggleCaseInnerParagraph", func(args ActionArgs) Action {
+		return ToggleCaseInnerParagraph(args.Count)
+	})
+}
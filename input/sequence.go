@@ -0,0 +1,43 @@
+package input
+
+import (
+	"github.com/aretext/aretext/state"
+)
+
+// Sequence composes multiple actions into a single Action that runs them in
+// order and commits them as one undo entry, so the composite behaves like a
+// single editor command for undo/redo purposes.
+//
+// This is the building block for user-defined macros bound directly to a key
+// sequence (for example, via the string-named action registry): instead of
+// a one-off helper like EnterInsertModeAtEndOfLine, that behavior can be
+// expressed as Sequence(CursorLineEndIncludeEndOfLineOrFile, EnterInsertMode).
+func Sequence(actions ...Action) Action {
+	return func(s *state.EditorState) {
+		state.BeginUndoEntry(s)
+		for _, action := range actions {
+			action(s)
+		}
+		state.CommitUndoEntry(s)
+	}
+}
+
+// LookupActionSequence resolves a list of registered action names to a single
+// composite Action via Sequence. This lets a config binding specify a macro
+// as a list of action names (each with its own args) instead of a single
+// name. The second return value is false if any name isn't registered.
+func LookupActionSequence(names []string, argsList []ActionArgs) (Action, bool) {
+	actions := make([]Action, 0, len(names))
+	for i, name := range names {
+		var args ActionArgs
+		if i < len(argsList) {
+			args = argsList[i]
+		}
+		action, ok := LookupAction(name, args)
+		if !ok {
+			return nil, false
+		}
+		actions = append(actions, action)
+	}
+	return Sequence(actions...), true
+}
@@ -0,0 +1,199 @@
+package text
+
+import (
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func readAll(t *testing.T, tree *Tree, charPos uint64) string {
+	t.Helper()
+	c := tree.CursorAtPosition(charPos)
+	var sb strings.Builder
+	var buf [64]byte
+	for {
+		n, err := c.Read(buf[:])
+		sb.Write(buf[:n])
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("Read: %v", err)
+		}
+	}
+	return sb.String()
+}
+
+func readAllReverse(t *testing.T, tree *Tree, charPos uint64, bufSize int) string {
+	t.Helper()
+	c := tree.CursorAtPosition(charPos)
+	var got []byte
+	buf := make([]byte, bufSize)
+	for {
+		n, err := c.ReadReverse(buf)
+		chunk := append([]byte(nil), buf[:n]...)
+		got = append(chunk, got...)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("ReadReverse: %v", err)
+		}
+	}
+	return string(got)
+}
+
+func TestCursorReadReverseRoundTrip(t *testing.T) {
+	testCases := []struct {
+		name string
+		text string
+	}{
+		{name: "empty", text: ""},
+		{name: "ascii", text: "hello, world"},
+		{name: "multiline", text: "hello, world\nsecond line\nthird line\n"},
+		{name: "multibyte", text: "hello, 世界\nsecond line\n"},
+		{name: "long", text: strings.Repeat("abcdefghij ", 30)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tree, err := NewTreeFromString(tc.text)
+			if err != nil {
+				t.Fatalf("NewTreeFromString: %v", err)
+			}
+
+			// ReadReverse must never split a multi-byte character, so a
+			// buffer must be at least utf8.UTFMax bytes to guarantee
+			// progress; exercise several sizes at or above that floor.
+			for _, bufSize := range []int{utf8.UTFMax, utf8.UTFMax + 3, 64} {
+				n := uint64(utf8.RuneCountInString(tc.text))
+				got := readAllReverse(t, tree, n, bufSize)
+				if got != tc.text {
+					t.Errorf("bufSize %d: got %q, want %q", bufSize, got, tc.text)
+				}
+			}
+		})
+	}
+}
+
+func TestCursorReadReverseFromMidpoint(t *testing.T) {
+	text := "hello, 世界\nsecond line with more text\n"
+	tree, err := NewTreeFromString(text)
+	if err != nil {
+		t.Fatalf("NewTreeFromString: %v", err)
+	}
+
+	runes := []rune(text)
+	for pos := 0; pos <= len(runes); pos++ {
+		want := string(runes[:pos])
+		got := readAllReverse(t, tree, uint64(pos), 64)
+		if got != want {
+			t.Errorf("pos %d: got %q, want %q", pos, got, want)
+		}
+	}
+}
+
+func TestCursorReadReverseRandomized(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	runeset := []rune("abc 日本語\n.,;")
+
+	for trial := 0; trial < 50; trial++ {
+		var sb strings.Builder
+		for i, n := 0, rng.Intn(200); i < n; i++ {
+			sb.WriteRune(runeset[rng.Intn(len(runeset))])
+		}
+		text := sb.String()
+
+		tree, err := NewTreeFromString(text)
+		if err != nil {
+			t.Fatalf("NewTreeFromString: %v", err)
+		}
+
+		runes := []rune(text)
+		pos := rng.Intn(len(runes) + 1)
+		want := string(runes[:pos])
+
+		bufSize := utf8.UTFMax + rng.Intn(6)
+		got := readAllReverse(t, tree, uint64(pos), bufSize)
+		if got != want {
+			t.Fatalf("trial %d, pos %d, bufSize %d: got %q, want %q", trial, pos, bufSize, got, want)
+		}
+	}
+}
+
+// TestByteOffsetOfPrevCharMidRuneLeaf guards against a uint64 underflow in
+// byteOffsetOfPrevChar: if a leaf somehow starts with a UTF-8 continuation
+// byte (violating the "never split a rune across leaves" invariant), the
+// backward scan must stop at index 0 instead of wrapping around.
+func TestByteOffsetOfPrevCharMidRuneLeaf(t *testing.T) {
+	var l leafNode
+	l.numBytes = 3
+	copy(l.textBytes[:], []byte{0x80, 0x80, 'a'}) // two stray continuation bytes, then an ASCII byte
+
+	got := l.byteOffsetOfPrevChar(1)
+	if got != 0 {
+		t.Errorf("byteOffsetOfPrevChar(1) = %d, want 0", got)
+	}
+}
+
+func TestTreeByteOffsetAtPosition(t *testing.T) {
+	text := "ab日cd\n世界"
+	tree, err := NewTreeFromString(text)
+	if err != nil {
+		t.Fatalf("NewTreeFromString: %v", err)
+	}
+
+	byteOffset := uint64(0)
+	for i, r := range []rune(text) {
+		got := tree.ByteOffsetAtPosition(uint64(i))
+		if got != byteOffset {
+			t.Errorf("pos %d: got byte offset %d, want %d", i, got, byteOffset)
+		}
+		byteOffset += uint64(utf8.RuneLen(r))
+	}
+
+	if got := tree.ByteOffsetAtPosition(uint64(len([]rune(text)))); got != byteOffset {
+		t.Errorf("end position: got byte offset %d, want %d", got, byteOffset)
+	}
+}
+
+func TestTreeCursorAtByteOffset(t *testing.T) {
+	text := "ab日cd\n世界"
+	tree, err := NewTreeFromString(text)
+	if err != nil {
+		t.Fatalf("NewTreeFromString: %v", err)
+	}
+
+	byteOffset := uint64(0)
+	for i := range []rune(text) {
+		want := readAll(t, tree, uint64(i))
+		c := tree.CursorAtByteOffset(byteOffset)
+		var sb strings.Builder
+		var buf [64]byte
+		for {
+			n, err := c.Read(buf[:])
+			sb.Write(buf[:n])
+			if err == io.EOF {
+				break
+			}
+		}
+		if sb.String() != want {
+			t.Errorf("byte offset %d: got %q, want %q", byteOffset, sb.String(), want)
+		}
+		byteOffset += uint64(utf8.RuneLen([]rune(text)[i]))
+	}
+}
+
+func TestTreeCursorAtByteOffsetPastEnd(t *testing.T) {
+	tree, err := NewTreeFromString("abc")
+	if err != nil {
+		t.Fatalf("NewTreeFromString: %v", err)
+	}
+
+	c := tree.CursorAtByteOffset(100)
+	var buf [16]byte
+	n, err := c.Read(buf[:])
+	if n != 0 || err != io.EOF {
+		t.Errorf("got n=%d err=%v, want n=0 err=io.EOF", n, err)
+	}
+}
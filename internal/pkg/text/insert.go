@@ -0,0 +1,303 @@
+package text
+
+import (
+	"errors"
+	"unicode/utf8"
+)
+
+// InsertAtPosition inserts s (which must be valid UTF-8) at the UTF-8
+// character position charPos. If charPos is past the end of the text, this
+// appends s. Long inserts are applied one leaf's worth of bytes at a time,
+// so a single call can never require more than one new leaf node per level
+// of the tree. Only the path from the root down to each affected leaf is
+// replaced; every other node is shared with whatever Snapshot the tree had
+// before the call, so a Snapshot taken before InsertAtPosition keeps reading
+// the old text.
+func (t *Tree) InsertAtPosition(charPos uint64, s string) error {
+	v := NewValidator()
+	if !v.ValidateBytes([]byte(s)) || !v.ValidateEnd() {
+		return errors.New("invalid UTF-8")
+	}
+
+	for len(s) > 0 {
+		chunk := s
+		if len(chunk) > maxInsertChunkBytes {
+			chunk = chunk[:truncateToCharBoundary([]byte(chunk), maxInsertChunkBytes)]
+		}
+
+		newRoot := *t.root
+		splitGroup := newRoot.insertAtPosition(charPos, chunk)
+		t.root = &newRoot
+
+		if splitGroup != nil {
+			t.growRoot(splitGroup)
+		}
+
+		charPos += uint64(utf8.RuneCountInString(chunk))
+		s = s[len(chunk):]
+	}
+
+	return nil
+}
+
+// growRoot adds a level to the tree after the root's child group has
+// split: the old root becomes one entry in a brand new root's child
+// group, alongside a new sibling entry wrapping splitGroup. This mirrors
+// how buildTreeFromLeaves grows the tree level by level during bulk load.
+func (t *Tree) growRoot(splitGroup nodeGroup) {
+	oldRoot := *t.root
+
+	newSibling := innerNode{child: splitGroup}
+	newSibling.recalculateChildKeys()
+
+	newRootGroup := &innerNodeGroup{numNodes: 2}
+	newRootGroup.nodes[0] = oldRoot
+	newRootGroup.nodes[1] = newSibling
+
+	newRoot := &innerNode{child: newRootGroup}
+	newRoot.recalculateChildKeys()
+	t.root = newRoot
+}
+
+func (n *innerNode) insertAtPosition(charPos uint64, s string) (splitGroup nodeGroup) {
+	nodeIdx, adjustedCharPos := n.locatePosition(charPos)
+	newChild, splitGroup := n.child.insertAtPosition(nodeIdx, adjustedCharPos, s)
+	n.child = newChild
+	n.recalculateChildKeys()
+	return splitGroup
+}
+
+func (g *innerNodeGroup) insertAtPosition(nodeIdx uint64, charPos uint64, s string) (nodeGroup, nodeGroup) {
+	newGroup := g.clone()
+	splitGroup := newGroup.nodes[nodeIdx].insertAtPosition(charPos, s)
+	if splitGroup == nil {
+		return newGroup, nil
+	}
+
+	newNode := innerNode{child: splitGroup}
+	newNode.recalculateChildKeys()
+	return newGroup, newGroup.insertNode(nodeIdx+1, newNode)
+}
+
+// insertNode inserts newNode at idx, shifting later nodes over, unless the
+// group is already full, in which case it splits the maxNodesPerGroup+1
+// nodes evenly between this group and a new sibling and returns the
+// sibling. The receiver must already be a private clone, since it's
+// mutated in place.
+func (g *innerNodeGroup) insertNode(idx uint64, newNode innerNode) nodeGroup {
+	if g.numNodes < maxNodesPerGroup {
+		for i := g.numNodes; i > idx; i-- {
+			g.nodes[i] = g.nodes[i-1]
+		}
+		g.nodes[idx] = newNode
+		g.numNodes++
+		return nil
+	}
+
+	var combined [maxNodesPerGroup + 1]innerNode
+	copy(combined[:idx], g.nodes[:idx])
+	combined[idx] = newNode
+	copy(combined[idx+1:], g.nodes[idx:g.numNodes])
+
+	mid := uint64(len(combined)) / 2
+	newGroup := &innerNodeGroup{numNodes: uint64(len(combined)) - mid}
+	copy(newGroup.nodes[:], combined[mid:])
+
+	g.numNodes = mid
+	copy(g.nodes[:], combined[:mid])
+
+	return newGroup
+}
+
+func (g *leafNodeGroup) insertAtPosition(nodeIdx uint64, charPos uint64, s string) (nodeGroup, nodeGroup) {
+	newGroup := g.clone()
+
+	leaf := &newGroup.nodes[nodeIdx]
+	sBytes := []byte(s)
+
+	if uint64(leaf.numBytes)+uint64(len(sBytes)) <= maxBytesPerLeaf {
+		leaf.insertAtPosition(charPos, sBytes)
+		return newGroup, nil
+	}
+
+	offset := leaf.byteOffsetForPosition(charPos)
+	combined := make([]byte, 0, uint64(leaf.numBytes)+uint64(len(sBytes)))
+	combined = append(combined, leaf.textBytes[:offset]...)
+	combined = append(combined, sBytes...)
+	combined = append(combined, leaf.textBytes[offset:leaf.numBytes]...)
+
+	splitAt := splitAtCharBoundary(combined, maxBytesPerLeaf)
+	leaf.numBytes = byte(splitAt)
+	copy(leaf.textBytes[:], combined[:splitAt])
+
+	var newLeaf leafNode
+	newLeaf.numBytes = byte(len(combined) - splitAt)
+	copy(newLeaf.textBytes[:], combined[splitAt:])
+
+	return newGroup, newGroup.insertNode(nodeIdx+1, newLeaf)
+}
+
+// insertNode inserts newLeaf at idx, shifting later nodes over, unless the
+// group is already full, in which case it splits the maxNodesPerGroup+1
+// nodes evenly between this group and a new sibling. The receiver must
+// already be a private clone, since it's mutated in place.
+func (g *leafNodeGroup) insertNode(idx uint64, newLeaf leafNode) nodeGroup {
+	if g.numNodes < maxNodesPerGroup {
+		for i := g.numNodes; i > idx; i-- {
+			g.nodes[i] = g.nodes[i-1]
+		}
+		g.nodes[idx] = newLeaf
+		g.numNodes++
+		return nil
+	}
+
+	var combined [maxNodesPerGroup + 1]leafNode
+	copy(combined[:idx], g.nodes[:idx])
+	combined[idx] = newLeaf
+	copy(combined[idx+1:], g.nodes[idx:g.numNodes])
+
+	mid := uint64(len(combined)) / 2
+	newGroup := &leafNodeGroup{numNodes: uint64(len(combined)) - mid}
+	copy(newGroup.nodes[:], combined[mid:])
+
+	g.numNodes = mid
+	copy(g.nodes[:], combined[:mid])
+
+	return newGroup
+}
+
+func (l *leafNode) insertAtPosition(charPos uint64, s []byte) {
+	offset := l.byteOffsetForPosition(charPos)
+	copy(l.textBytes[offset+uint64(len(s)):], l.textBytes[offset:l.numBytes])
+	copy(l.textBytes[offset:], s)
+	l.numBytes += byte(len(s))
+}
+
+// mergeIfUnderfull merges the leaf at nodeIdx, which the caller just
+// deleted a character from, with a same-group neighbor when the combined
+// size still fits in one leaf, or otherwise redistributes bytes between
+// them evenly. Without this, a long editing session of small inserts and
+// deletes would leave every touched leaf just under half empty forever,
+// since a plain delete only ever shrinks the leaf it touched. The receiver
+// must already be a private clone, since it's mutated in place.
+func (g *leafNodeGroup) mergeIfUnderfull(nodeIdx uint64) {
+	if g.nodes[nodeIdx].numBytes >= maxBytesPerLeaf/2 {
+		return
+	}
+
+	if nodeIdx+1 < g.numNodes {
+		g.mergeOrRedistribute(nodeIdx, nodeIdx+1)
+	} else if nodeIdx > 0 {
+		g.mergeOrRedistribute(nodeIdx-1, nodeIdx)
+	}
+}
+
+func (g *leafNodeGroup) mergeOrRedistribute(leftIdx, rightIdx uint64) {
+	left := &g.nodes[leftIdx]
+	right := &g.nodes[rightIdx]
+
+	combined := make([]byte, 0, uint64(left.numBytes)+uint64(right.numBytes))
+	combined = append(combined, left.textBytes[:left.numBytes]...)
+	combined = append(combined, right.textBytes[:right.numBytes]...)
+
+	if len(combined) <= maxBytesPerLeaf {
+		left.numBytes = byte(len(combined))
+		copy(left.textBytes[:], combined)
+		g.removeNode(rightIdx)
+		return
+	}
+
+	splitAt := splitAtCharBoundary(combined, maxBytesPerLeaf)
+	left.numBytes = byte(splitAt)
+	copy(left.textBytes[:], combined[:splitAt])
+	right.numBytes = byte(len(combined) - splitAt)
+	copy(right.textBytes[:], combined[splitAt:])
+}
+
+// removeNode deletes the now-empty node at idx by shifting later nodes
+// left over it.
+func (g *leafNodeGroup) removeNode(idx uint64) {
+	for i := idx; i < g.numNodes-1; i++ {
+		g.nodes[i] = g.nodes[i+1]
+	}
+	g.numNodes--
+}
+
+// maxInsertChunkBytes caps how many bytes of a multi-chunk insert (see
+// Tree.InsertAtPosition) land in a single fast-path chunk. Combined with an
+// already-full leaf (maxBytesPerLeaf bytes), this keeps the buffer handed to
+// splitAtCharBoundary at or below 2*maxBytesPerLeaf-(utf8.UTFMax-1) bytes,
+// which is the largest size for which the window splitAtCharBoundary
+// searches is guaranteed to contain a UTF-8 boundary: the window is at
+// least utf8.UTFMax bytes wide, and a rune is at most utf8.UTFMax bytes, so
+// it can't span the whole window without a start byte in it.
+const maxInsertChunkBytes = maxBytesPerLeaf - (utf8.UTFMax - 1)
+
+// splitAtCharBoundary returns an index near the midpoint of b that falls on
+// a UTF-8 character boundary, so splitting b between two leaves never
+// divides a multi-byte rune between them. The result is clamped to
+// [len(b)-maxLeafBytes, maxLeafBytes] (intersected with [0, len(b)]) so that,
+// as long as the window is at least utf8.UTFMax bytes wide, neither b[:i]
+// nor b[i:] can exceed maxLeafBytes bytes. Callers are responsible for
+// keeping len(b) within the headroom that guarantees the window is wide
+// enough (see maxInsertChunkBytes); if a caller hands over more than that,
+// the window may be too narrow to contain a boundary, and this still never
+// returns an index that divides a rune -- it widens the search outward past
+// the window instead, which may let one side exceed maxLeafBytes.
+func splitAtCharBoundary(b []byte, maxLeafBytes int) int {
+	lo := 0
+	if len(b) > maxLeafBytes {
+		lo = len(b) - maxLeafBytes
+	}
+	hi := len(b)
+	if hi > maxLeafBytes {
+		hi = maxLeafBytes
+	}
+
+	mid := len(b) / 2
+	if mid < lo {
+		mid = lo
+	} else if mid > hi {
+		mid = hi
+	}
+
+	isBoundary := func(i int) bool {
+		return i == len(b) || utf8StartByteIndicator[b[i]] != 0
+	}
+
+	for i := mid; i <= hi; i++ {
+		if isBoundary(i) {
+			return i
+		}
+	}
+	for i := mid - 1; i >= lo; i-- {
+		if isBoundary(i) {
+			return i
+		}
+	}
+	for i := hi + 1; i < len(b); i++ {
+		if isBoundary(i) {
+			return i
+		}
+	}
+	for i := lo - 1; i > 0; i-- {
+		if isBoundary(i) {
+			return i
+		}
+	}
+	return 0
+}
+
+// truncateToCharBoundary returns the largest index no greater than maxLen
+// that falls on a UTF-8 character boundary of b, so a chunk taken from a
+// longer insert never ends mid-rune.
+func truncateToCharBoundary(b []byte, maxLen int) int {
+	if maxLen >= len(b) {
+		return len(b)
+	}
+	n := maxLen
+	for n > 0 && utf8StartByteIndicator[b[n]] == 0 {
+		n--
+	}
+	return n
+}
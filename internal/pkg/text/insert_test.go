@@ -0,0 +1,79 @@
+package text
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// TestSplitAtCharBoundaryNeverDividesRune checks splitAtCharBoundary across
+// every rune width and every position a rune could straddle the clamped
+// window, for a combined buffer at the largest size a caller is expected to
+// hand over (2*maxLeafBytes-(utf8.UTFMax-1), see maxInsertChunkBytes): the
+// window is exactly utf8.UTFMax bytes wide in that case, so it must still
+// always land on a boundary.
+func TestSplitAtCharBoundaryNeverDividesRune(t *testing.T) {
+	const maxLeafBytes = 16
+	runes := []rune{'a', 'é', '世', '𝔘'}
+
+	for _, r := range runes {
+		width := utf8.RuneLen(r)
+		// Build a buffer of exactly the headroom-safe combined size, with
+		// the rune r placed at every byte offset it could possibly occupy.
+		total := 2*maxLeafBytes - (utf8.UTFMax - 1)
+		for offset := 0; offset+width <= total; offset++ {
+			b := make([]byte, total)
+			for i := range b {
+				b[i] = 'x'
+			}
+			utf8.EncodeRune(b[offset:], r)
+
+			splitAt := splitAtCharBoundary(b, maxLeafBytes)
+			if splitAt > offset && splitAt < offset+width {
+				t.Fatalf("rune %q at offset %d: splitAtCharBoundary(maxLeafBytes=%d) = %d divides it",
+					r, offset, maxLeafBytes, splitAt)
+			}
+		}
+	}
+}
+
+// TestInsertAtPositionNeverSplitsRuneAcrossLeaves bulk-loads
+// maxBytesPerLeaf bytes (which fill one leaf to maxBytesPerLeaf-1 bytes
+// and start a second), then inserts a multi-byte rune near that leaf
+// boundary: the resulting tree must never divide that rune across two
+// ReadReverse calls, even when the reverse buffer is exactly utf8.UTFMax
+// bytes -- the smallest buffer guaranteed to fit any single rune.
+func TestInsertAtPositionNeverSplitsRuneAcrossLeaves(t *testing.T) {
+	tree, err := NewTreeFromString(strings.Repeat("a", maxBytesPerLeaf))
+	if err != nil {
+		t.Fatalf("NewTreeFromString: %v", err)
+	}
+
+	inserted := "\U0001D518" + strings.Repeat("a", maxBytesPerLeaf-4)
+	if err := tree.InsertAtPosition(maxBytesPerLeaf-1, inserted); err != nil {
+		t.Fatalf("InsertAtPosition: %v", err)
+	}
+
+	want := strings.Repeat("a", maxBytesPerLeaf-1) + inserted + "a"
+	n := uint64(utf8.RuneCountInString(want))
+
+	c := tree.CursorAtPosition(n)
+	buf := make([]byte, utf8.UTFMax)
+	var got []byte
+	for {
+		rn, err := c.ReadReverse(buf)
+		if rn > 0 {
+			if !utf8.Valid(buf[:rn]) {
+				t.Fatalf("ReadReverse returned a partial, invalid UTF-8 chunk: %x", buf[:rn])
+			}
+			got = append(append([]byte(nil), buf[:rn]...), got...)
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	if string(got) != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
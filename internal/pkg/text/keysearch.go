@@ -0,0 +1,26 @@
+package text
+
+// keySearch returns the index of the first entry in cum[0:numKeys] (a
+// cumulative-sum array, cum[i] == sum of keys[0..i] inclusive) that
+// exceeds target, or numKeys-1 if no entry exceeds it. This is the same
+// result as the naive "subtract running totals until charPos fits" loop
+// over keys, just phrased as a search over a precomputed prefix sum so it
+// can be vectorized: see keySearchAVX2 in keysearch_amd64.s.
+//
+// Below eight keys the fixed overhead of a SIMD compare isn't worth it,
+// so this always falls back to the portable linear scan in that case.
+func keySearch(cum []uint32, numKeys int, target uint32) int {
+	if numKeys >= 8 && hasAVX2 {
+		return keySearchAVX2(&cum[0], numKeys, target)
+	}
+	return keySearchGeneric(cum[:numKeys], target)
+}
+
+func keySearchGeneric(cum []uint32, target uint32) int {
+	for i, c := range cum {
+		if c > target {
+			return i
+		}
+	}
+	return len(cum) - 1
+}
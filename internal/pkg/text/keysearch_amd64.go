@@ -0,0 +1,18 @@
+package text
+
+// hasAVX2 is detected once at startup via CPUID (see cpuHasAVX2 in
+// keysearch_amd64.s) so keySearch can skip the vectorized path entirely on
+// older hardware instead of faulting on an unsupported instruction.
+var hasAVX2 = cpuHasAVX2()
+
+//go:noescape
+func cpuHasAVX2() bool
+
+// keySearchAVX2 is the vectorized counterpart to keySearchGeneric: it
+// compares target against 8 lanes of cum at a time with a single
+// VPCMPGTD, extracts the lane-match mask with VPMOVMSKB, and finds the
+// first matching lane with TZCNT, rather than branching per key like the
+// scalar loop does.
+//
+//go:noescape
+func keySearchAVX2(cum *uint32, numKeys int, target uint32) int
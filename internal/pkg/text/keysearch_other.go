@@ -0,0 +1,7 @@
+//go:build !amd64
+
+package text
+
+// hasAVX2 is always false off amd64; keySearch falls back to
+// keySearchGeneric on every other architecture.
+var hasAVX2 = false
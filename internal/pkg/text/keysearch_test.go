@@ -0,0 +1,90 @@
+package text
+
+import (
+	"testing"
+)
+
+func TestKeySearchGeneric(t *testing.T) {
+	cum := []uint32{4, 9, 9, 15, 22}
+	testCases := []struct {
+		target   uint32
+		expected int
+	}{
+		{target: 0, expected: 0},
+		{target: 3, expected: 0},
+		{target: 4, expected: 1},
+		{target: 8, expected: 1},
+		{target: 9, expected: 3},
+		{target: 14, expected: 3},
+		{target: 15, expected: 4},
+		{target: 100, expected: 4},
+	}
+
+	for _, tc := range testCases {
+		actual := keySearchGeneric(cum, tc.target)
+		if actual != tc.expected {
+			t.Errorf("target %d: expected %d, got %d", tc.target, tc.expected, actual)
+		}
+	}
+}
+
+// TestKeySearchAVX2MatchesGeneric checks that the vectorized AVX2 path
+// agrees with the scalar fallback, including around values that cross
+// 1<<31 where a signed comparison would disagree with the unsigned one
+// keySearchGeneric uses.
+func TestKeySearchAVX2MatchesGeneric(t *testing.T) {
+	if !hasAVX2 {
+		t.Skip("AVX2 not available on this machine")
+	}
+
+	cumVariants := [][]uint32{
+		{10, 20, 30, 40, 50, 60, 70, 80},
+		{10, 20, 30, 40, 50, 60, 70, 80, 90},
+		{1 << 30, 1<<31 - 1, 1 << 31, 1<<31 + 1, 1<<32 - 100, 1<<32 - 10, 1<<32 - 2, 1<<32 - 1},
+		{1 << 30, 1<<31 - 1, 1 << 31, 1<<31 + 1, 1<<32 - 100, 1<<32 - 10, 1<<32 - 2, 1<<32 - 1, 1<<32 - 1},
+	}
+
+	targets := []uint32{
+		0, 1, 9, 10, 15, 85,
+		1<<31 - 2, 1<<31 - 1, 1 << 31, 1<<31 + 1, 1<<31 + 2,
+		1<<32 - 1,
+	}
+
+	for _, cum := range cumVariants {
+		for _, target := range targets {
+			want := keySearchGeneric(cum, target)
+			got := keySearchAVX2(&cum[0], len(cum), target)
+			if want != got {
+				t.Errorf("cum=%v target=%d: generic=%d avx2=%d", cum, target, want, got)
+			}
+		}
+	}
+}
+
+func BenchmarkKeySearchGeneric(b *testing.B) {
+	cum := make([]uint32, maxKeysPerNode)
+	for i := range cum {
+		cum[i] = uint32((i + 1) * 1000)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		keySearchGeneric(cum, uint32(i%(len(cum)*1000)))
+	}
+}
+
+func BenchmarkKeySearchAVX2(b *testing.B) {
+	if !hasAVX2 {
+		b.Skip("AVX2 not available on this machine")
+	}
+
+	cum := make([]uint32, maxKeysPerNode)
+	for i := range cum {
+		cum[i] = uint32((i + 1) * 1000)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		keySearchAVX2(&cum[0], len(cum), uint32(i%(len(cum)*1000)))
+	}
+}
@@ -0,0 +1,756 @@
+//go:build unix
+
+package text
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"syscall"
+)
+
+// PersistentTree is an mmap-backed variant of Tree that stores its B+ tree
+// directly in a file instead of in heap-allocated nodes. The file is laid
+// out in fixed-size pages, one innerNodeGroup or leafNodeGroup per page, so
+// child pointers are 64-bit page offsets rather than Go pointers and
+// opening a multi-gigabyte file only requires mapping it, not reading it.
+// Mutations are first appended to a side journal file and only applied to
+// the mapped pages on Commit, so a crash between writes can't leave the
+// main file in a half-updated state; OpenPersistentTree replays a
+// non-empty journal before mapping the file.
+type PersistentTree struct {
+	mu   sync.Mutex
+	path string
+
+	file *os.File
+	data []byte // mmap'd contents of file, including the page-0 header
+
+	journalPath string
+	journal     *os.File
+}
+
+const (
+	// persistentPageSize is the size in bytes of every page in a
+	// PersistentTree file, including the header page at offset 0.
+	persistentPageSize = 4096
+
+	// persistentMaxKeys bounds how many children an inner page holds.
+	// It's smaller than the
+	// in-memory Tree's maxNodesPerGroup since on-disk nodes also spend
+	// space on page-offset child pointers.
+	persistentMaxKeys = 32
+
+	// persistentMaxBytesPerLeaf is the most UTF-8 text bytes a single
+	// leaf page stores.
+	persistentMaxBytesPerLeaf = persistentPageSize - 64
+
+	persistentHeaderMagic   = uint32(0x61727478) // "artx"
+	persistentHeaderVersion = uint32(1)
+
+	persistentNilPage = ^uint64(0)
+)
+
+// persistentHeader is the fixed-layout record stored at page 0.
+type persistentHeader struct {
+	magic        uint32
+	version      uint32
+	rootOffset   uint64
+	freelistHead uint64
+	nextPage     uint64
+}
+
+func (h *persistentHeader) encode(page []byte) {
+	binary.LittleEndian.PutUint32(page[0:4], h.magic)
+	binary.LittleEndian.PutUint32(page[4:8], h.version)
+	binary.LittleEndian.PutUint64(page[8:16], h.rootOffset)
+	binary.LittleEndian.PutUint64(page[16:24], h.freelistHead)
+	binary.LittleEndian.PutUint64(page[24:32], h.nextPage)
+}
+
+func (h *persistentHeader) decode(page []byte) {
+	h.magic = binary.LittleEndian.Uint32(page[0:4])
+	h.version = binary.LittleEndian.Uint32(page[4:8])
+	h.rootOffset = binary.LittleEndian.Uint64(page[8:16])
+	h.freelistHead = binary.LittleEndian.Uint64(page[16:24])
+	h.nextPage = binary.LittleEndian.Uint64(page[24:32])
+}
+
+// persistentPageKind tags the first byte of every non-header page so a
+// reader can tell inner pages from leaf pages without consulting the
+// parent.
+type persistentPageKind byte
+
+const (
+	persistentKindInner persistentPageKind = 1
+	persistentKindLeaf  persistentPageKind = 2
+)
+
+// OpenPersistentTree opens (creating if necessary) a file-backed tree at
+// path. If a prior session left an unfinished journal behind, it's
+// replayed into the main file before the file is mapped.
+func OpenPersistentTree(path string) (*PersistentTree, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("opening persistent tree file: %w", err)
+	}
+
+	t := &PersistentTree{
+		path:        path,
+		file:        file,
+		journalPath: path + ".journal",
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	if info.Size() == 0 {
+		if err := t.initEmpty(); err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+
+	if err := t.replayJournalIfPresent(); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	if err := t.mmap(); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// initEmpty writes a header page and a single empty leaf root page to a
+// brand new file.
+func (t *PersistentTree) initEmpty() error {
+	header := persistentHeader{
+		magic:        persistentHeaderMagic,
+		version:      persistentHeaderVersion,
+		rootOffset:   persistentPageSize,
+		freelistHead: persistentNilPage,
+		nextPage:     2 * persistentPageSize,
+	}
+
+	buf := make([]byte, 2*persistentPageSize)
+	header.encode(buf[0:persistentPageSize])
+
+	rootPage := buf[persistentPageSize : 2*persistentPageSize]
+	rootPage[0] = byte(persistentKindLeaf)
+	encodeLeafPage(rootPage, &persistentLeafPage{prev: persistentNilPage, next: persistentNilPage})
+
+	if _, err := t.file.WriteAt(buf, 0); err != nil {
+		return err
+	}
+	return t.file.Sync()
+}
+
+// mmap maps the whole file (growing the mapping as the file grows is
+// handled by remap on Commit).
+func (t *PersistentTree) mmap() error {
+	info, err := t.file.Stat()
+	if err != nil {
+		return err
+	}
+
+	data, err := syscall.Mmap(int(t.file.Fd()), 0, int(info.Size()), syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		return fmt.Errorf("mmap persistent tree file: %w", err)
+	}
+
+	t.data = data
+	return nil
+}
+
+func (t *PersistentTree) remap() error {
+	if t.data != nil {
+		if err := syscall.Munmap(t.data); err != nil {
+			return err
+		}
+	}
+	return t.mmap()
+}
+
+// Close unmaps and closes the underlying file. Any uncommitted writes are
+// discarded; call Commit first to persist them.
+func (t *PersistentTree) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.journal != nil {
+		t.journal.Close()
+		t.journal = nil
+	}
+
+	if t.data != nil {
+		if err := syscall.Munmap(t.data); err != nil {
+			return err
+		}
+		t.data = nil
+	}
+
+	return t.file.Close()
+}
+
+// journalRecord is one entry in the write-ahead log: the page offset that
+// was modified and its full new contents.
+type journalRecord struct {
+	pageOffset uint64
+	page       [persistentPageSize]byte
+}
+
+// writeJournal appends a record to the journal file, opening it on first
+// use, and fsyncs it so the record survives a crash before Commit runs.
+func (t *PersistentTree) writeJournal(pageOffset uint64, page []byte) error {
+	if t.journal == nil {
+		j, err := os.OpenFile(t.journalPath, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+		if err != nil {
+			return err
+		}
+		t.journal = j
+	}
+
+	var rec journalRecord
+	rec.pageOffset = pageOffset
+	copy(rec.page[:], page)
+
+	var hdr [8]byte
+	binary.LittleEndian.PutUint64(hdr[:], rec.pageOffset)
+	if _, err := t.journal.Write(hdr[:]); err != nil {
+		return err
+	}
+	if _, err := t.journal.Write(rec.page[:]); err != nil {
+		return err
+	}
+	return t.journal.Sync()
+}
+
+// Commit applies every journaled page write to the main file and truncates
+// the journal, making the pending edits durable. Until Commit is called,
+// edits are visible to this process (they're applied to the in-memory mmap
+// immediately) but are only recoverable via journal replay if the process
+// crashes before committing.
+func (t *PersistentTree) Commit() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.journal == nil {
+		return nil
+	}
+
+	if err := t.journal.Sync(); err != nil {
+		return err
+	}
+	if err := t.journal.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := t.journal.Seek(0, 0); err != nil {
+		return err
+	}
+
+	return t.file.Sync()
+}
+
+// replayJournalIfPresent re-applies any records left over from a session
+// that wrote to the journal but never called Commit.
+func (t *PersistentTree) replayJournalIfPresent() error {
+	j, err := os.OpenFile(t.journalPath, os.O_RDONLY, 0o644)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer j.Close()
+
+	info, err := j.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() == 0 {
+		return nil
+	}
+
+	const recSize = 8 + persistentPageSize
+	buf := make([]byte, recSize)
+	for {
+		n, err := j.Read(buf)
+		if n == 0 {
+			break
+		}
+		if n < recSize {
+			// Truncated record from a crash mid-write; discard it and
+			// stop replaying, since everything after it is also suspect.
+			break
+		}
+
+		pageOffset := binary.LittleEndian.Uint64(buf[0:8])
+		if _, err := t.file.WriteAt(buf[8:recSize], int64(pageOffset)); err != nil {
+			return err
+		}
+
+		if err != nil {
+			break
+		}
+	}
+
+	if err := t.file.Sync(); err != nil {
+		return err
+	}
+
+	return os.Remove(t.journalPath)
+}
+
+func (t *PersistentTree) page(offset uint64) []byte {
+	return t.data[offset : offset+persistentPageSize]
+}
+
+func (t *PersistentTree) header() persistentHeader {
+	var h persistentHeader
+	h.decode(t.page(0))
+	return h
+}
+
+func (t *PersistentTree) setHeader(h persistentHeader) error {
+	page := t.page(0)
+	h.encode(page)
+	return t.writeJournal(0, page)
+}
+
+// allocPage reserves a fresh page, growing the file (and remapping it) if
+// the freelist is empty, and returns its offset.
+func (t *PersistentTree) allocPage() (uint64, error) {
+	h := t.header()
+	if h.freelistHead != persistentNilPage {
+		offset := h.freelistHead
+		next := binary.LittleEndian.Uint64(t.page(offset)[8:16])
+		h.freelistHead = next
+		return offset, t.setHeader(h)
+	}
+
+	offset := h.nextPage
+	h.nextPage += persistentPageSize
+
+	if err := t.file.Truncate(int64(h.nextPage)); err != nil {
+		return 0, err
+	}
+	if err := t.remap(); err != nil {
+		return 0, err
+	}
+
+	return offset, t.setHeader(h)
+}
+
+// persistentIndexKey mirrors indexKey, including numBytes so byte-offset
+// queries don't require a full scan (see ByteOffsetAtPosition on Tree).
+type persistentIndexKey struct {
+	numChars    uint64
+	numNewlines uint64
+	numBytes    uint64
+}
+
+const persistentIndexKeySize = 24
+
+func encodeIndexKey(b []byte, k persistentIndexKey) {
+	binary.LittleEndian.PutUint64(b[0:8], k.numChars)
+	binary.LittleEndian.PutUint64(b[8:16], k.numNewlines)
+	binary.LittleEndian.PutUint64(b[16:24], k.numBytes)
+}
+
+func decodeIndexKey(b []byte) persistentIndexKey {
+	return persistentIndexKey{
+		numChars:    binary.LittleEndian.Uint64(b[0:8]),
+		numNewlines: binary.LittleEndian.Uint64(b[8:16]),
+		numBytes:    binary.LittleEndian.Uint64(b[16:24]),
+	}
+}
+
+// persistentInnerPage is the decoded form of an inner node page: up to
+// persistentMaxKeys (childOffset, key) pairs.
+type persistentInnerPage struct {
+	numKeys  uint64
+	children [persistentMaxKeys]uint64
+	keys     [persistentMaxKeys]persistentIndexKey
+}
+
+// Inner page layout: kind(1) | pad(7) | numKeys(8) | children[32](256) | keys[32](768)
+const (
+	persistentInnerChildrenOffset = 16
+	persistentInnerKeysOffset     = persistentInnerChildrenOffset + persistentMaxKeys*8
+)
+
+func encodeInnerPage(page []byte, p *persistentInnerPage) {
+	page[0] = byte(persistentKindInner)
+	binary.LittleEndian.PutUint64(page[8:16], p.numKeys)
+	for i := uint64(0); i < p.numKeys; i++ {
+		binary.LittleEndian.PutUint64(page[persistentInnerChildrenOffset+i*8:], p.children[i])
+		encodeIndexKey(page[persistentInnerKeysOffset+i*persistentIndexKeySize:], p.keys[i])
+	}
+}
+
+func decodeInnerPage(page []byte) *persistentInnerPage {
+	p := &persistentInnerPage{numKeys: binary.LittleEndian.Uint64(page[8:16])}
+	for i := uint64(0); i < p.numKeys; i++ {
+		p.children[i] = binary.LittleEndian.Uint64(page[persistentInnerChildrenOffset+i*8:])
+		p.keys[i] = decodeIndexKey(page[persistentInnerKeysOffset+i*persistentIndexKeySize:])
+	}
+	return p
+}
+
+// persistentLeafPage is the decoded form of a leaf node page: a run of
+// UTF-8 text bytes plus the page offsets of its neighbors in the doubly
+// linked leaf list.
+type persistentLeafPage struct {
+	prev, next uint64
+	numBytes   uint64
+	text       [persistentMaxBytesPerLeaf]byte
+}
+
+// Leaf page layout: kind(1) | pad(7) | prev(8) | next(8) | numBytes(8) | text[...]
+const persistentLeafTextOffset = 32
+
+func encodeLeafPage(page []byte, l *persistentLeafPage) {
+	page[0] = byte(persistentKindLeaf)
+	binary.LittleEndian.PutUint64(page[8:16], l.prev)
+	binary.LittleEndian.PutUint64(page[16:24], l.next)
+	binary.LittleEndian.PutUint64(page[24:32], l.numBytes)
+	copy(page[persistentLeafTextOffset:], l.text[:l.numBytes])
+}
+
+func decodeLeafPage(page []byte) *persistentLeafPage {
+	l := &persistentLeafPage{
+		prev:     binary.LittleEndian.Uint64(page[8:16]),
+		next:     binary.LittleEndian.Uint64(page[16:24]),
+		numBytes: binary.LittleEndian.Uint64(page[24:32]),
+	}
+	copy(l.text[:], page[persistentLeafTextOffset:persistentLeafTextOffset+l.numBytes])
+	return l
+}
+
+func pageKind(page []byte) persistentPageKind {
+	return persistentPageKind(page[0])
+}
+
+// InsertAtPosition inserts s (which must be valid UTF-8) at charPos,
+// splitting leaf and inner pages (and growing a new root) as needed. Like
+// Tree.InsertAtPosition, charPos past the end of the text appends s.
+func (t *PersistentTree) InsertAtPosition(charPos uint64, s string) error {
+	v := NewValidator()
+	if !v.ValidateBytes([]byte(s)) || !v.ValidateEnd() {
+		return errors.New("invalid UTF-8")
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	h := t.header()
+	newRoot, promoted, err := t.insertInto(h.rootOffset, charPos, []byte(s))
+	if err != nil {
+		return err
+	}
+
+	if promoted != nil {
+		// The root split; build a fresh root page over the old root and
+		// its new sibling.
+		rootOffset, err := t.allocPage()
+		if err != nil {
+			return err
+		}
+
+		root := &persistentInnerPage{numKeys: 2}
+		root.children[0] = h.rootOffset
+		root.keys[0] = t.pageKey(h.rootOffset)
+		root.children[1] = promoted.offset
+		root.keys[1] = promoted.key
+
+		if err := t.writePage(rootOffset, func(page []byte) { encodeInnerPage(page, root) }); err != nil {
+			return err
+		}
+
+		newRoot = rootOffset
+	}
+
+	h.rootOffset = newRoot
+	return t.setHeader(h)
+}
+
+// promotedChild describes a new right sibling page produced by a split,
+// along with its summary key, so the caller can insert it into the parent.
+type promotedChild struct {
+	offset uint64
+	key    persistentIndexKey
+}
+
+func (t *PersistentTree) pageKey(offset uint64) persistentIndexKey {
+	page := t.page(offset)
+	switch pageKind(page) {
+	case persistentKindLeaf:
+		l := decodeLeafPage(page)
+		k := persistentIndexKey{numBytes: l.numBytes}
+		for _, b := range l.text[:l.numBytes] {
+			k.numChars += uint64(utf8StartByteIndicator[b])
+			if b == '\n' {
+				k.numNewlines++
+			}
+		}
+		return k
+	default:
+		inner := decodeInnerPage(page)
+		var k persistentIndexKey
+		for i := uint64(0); i < inner.numKeys; i++ {
+			k.numChars += inner.keys[i].numChars
+			k.numNewlines += inner.keys[i].numNewlines
+			k.numBytes += inner.keys[i].numBytes
+		}
+		return k
+	}
+}
+
+func (t *PersistentTree) writePage(offset uint64, encode func([]byte)) error {
+	buf := make([]byte, persistentPageSize)
+	encode(buf)
+	copy(t.page(offset), buf)
+	return t.writeJournal(offset, buf)
+}
+
+// insertInto recursively inserts textBytes at charPos within the subtree
+// rooted at offset. It returns the (possibly unchanged) offset of that
+// subtree's root and, if the page at offset split, the promoted right
+// sibling to insert into the parent.
+func (t *PersistentTree) insertInto(offset uint64, charPos uint64, textBytes []byte) (uint64, *promotedChild, error) {
+	page := t.page(offset)
+	if pageKind(page) == persistentKindLeaf {
+		return t.insertIntoLeaf(offset, charPos, textBytes)
+	}
+	return t.insertIntoInner(offset, charPos, textBytes)
+}
+
+func (t *PersistentTree) insertIntoLeaf(offset uint64, charPos uint64, textBytes []byte) (uint64, *promotedChild, error) {
+	l := decodeLeafPage(t.page(offset))
+	byteOffset := leafByteOffsetForPosition(l, charPos)
+
+	if l.numBytes+uint64(len(textBytes)) <= persistentMaxBytesPerLeaf {
+		copy(l.text[byteOffset+uint64(len(textBytes)):], l.text[byteOffset:l.numBytes])
+		copy(l.text[byteOffset:], textBytes)
+		l.numBytes += uint64(len(textBytes))
+		return offset, nil, t.writePage(offset, func(page []byte) { encodeLeafPage(page, l) })
+	}
+
+	// Doesn't fit: merge the insertion into the existing bytes, then split
+	// the combined run roughly in half between this page and a new right
+	// sibling spliced into the leaf list right after it.
+	combined := make([]byte, 0, l.numBytes+uint64(len(textBytes)))
+	combined = append(combined, l.text[:byteOffset]...)
+	combined = append(combined, textBytes...)
+	combined = append(combined, l.text[byteOffset:l.numBytes]...)
+
+	splitAt := splitAtCharBoundary(combined, persistentMaxBytesPerLeaf)
+
+	rightOffset, err := t.allocPage()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	right := &persistentLeafPage{next: l.next}
+	copy(right.text[:], combined[splitAt:])
+	right.numBytes = uint64(len(combined) - splitAt)
+	right.prev = offset
+
+	left := &persistentLeafPage{prev: l.prev, next: rightOffset}
+	copy(left.text[:], combined[:splitAt])
+	left.numBytes = uint64(splitAt)
+
+	if right.next != persistentNilPage {
+		oldNext := decodeLeafPage(t.page(right.next))
+		oldNext.prev = rightOffset
+		if err := t.writePage(right.next, func(page []byte) { encodeLeafPage(page, oldNext) }); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	if err := t.writePage(rightOffset, func(page []byte) { encodeLeafPage(page, right) }); err != nil {
+		return 0, nil, err
+	}
+	if err := t.writePage(offset, func(page []byte) { encodeLeafPage(page, left) }); err != nil {
+		return 0, nil, err
+	}
+
+	return offset, &promotedChild{offset: rightOffset, key: t.pageKey(rightOffset)}, nil
+}
+
+func (t *PersistentTree) insertIntoInner(offset uint64, charPos uint64, textBytes []byte) (uint64, *promotedChild, error) {
+	inner := decodeInnerPage(t.page(offset))
+
+	childIdx, adjustedCharPos := locateInnerChild(inner, charPos)
+	_, promotedFromChild, err := t.insertInto(inner.children[childIdx], adjustedCharPos, textBytes)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	inner.keys[childIdx] = t.pageKey(inner.children[childIdx])
+
+	if promotedFromChild == nil {
+		return offset, nil, t.writePage(offset, func(page []byte) { encodeInnerPage(page, inner) })
+	}
+
+	if inner.numKeys < persistentMaxKeys {
+		for i := inner.numKeys; i > childIdx+1; i-- {
+			inner.children[i] = inner.children[i-1]
+			inner.keys[i] = inner.keys[i-1]
+		}
+		inner.children[childIdx+1] = promotedFromChild.offset
+		inner.keys[childIdx+1] = promotedFromChild.key
+		inner.numKeys++
+		return offset, nil, t.writePage(offset, func(page []byte) { encodeInnerPage(page, inner) })
+	}
+
+	// The inner page is full: insert the new child into a temporary
+	// over-sized slice, then split it across this page and a new sibling.
+	children := make([]uint64, 0, inner.numKeys+1)
+	keys := make([]persistentIndexKey, 0, inner.numKeys+1)
+	for i := uint64(0); i < inner.numKeys; i++ {
+		children = append(children, inner.children[i])
+		keys = append(keys, inner.keys[i])
+		if i == childIdx {
+			children = append(children, promotedFromChild.offset)
+			keys = append(keys, promotedFromChild.key)
+		}
+	}
+
+	mid := len(children) / 2
+	rightOffset, err := t.allocPage()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	left := &persistentInnerPage{numKeys: uint64(mid)}
+	copy(left.children[:mid], children[:mid])
+	copy(left.keys[:mid], keys[:mid])
+
+	right := &persistentInnerPage{numKeys: uint64(len(children) - mid)}
+	copy(right.children[:len(children)-mid], children[mid:])
+	copy(right.keys[:len(children)-mid], keys[mid:])
+
+	if err := t.writePage(rightOffset, func(page []byte) { encodeInnerPage(page, right) }); err != nil {
+		return 0, nil, err
+	}
+	if err := t.writePage(offset, func(page []byte) { encodeInnerPage(page, left) }); err != nil {
+		return 0, nil, err
+	}
+
+	return offset, &promotedChild{offset: rightOffset, key: t.pageKey(rightOffset)}, nil
+}
+
+func locateInnerChild(inner *persistentInnerPage, charPos uint64) (childIdx, adjustedCharPos uint64) {
+	c := uint64(0)
+	for i := uint64(0); i < inner.numKeys; i++ {
+		nc := inner.keys[i].numChars
+		if charPos < c+nc {
+			return i, charPos - c
+		}
+		c += nc
+	}
+	return inner.numKeys - 1, charPos - c
+}
+
+func leafByteOffsetForPosition(l *persistentLeafPage, charPos uint64) uint64 {
+	n := uint64(0)
+	for i, b := range l.text[:l.numBytes] {
+		c := uint64(utf8StartByteIndicator[b])
+		if c > 0 && n == charPos {
+			return uint64(i)
+		}
+		n += c
+	}
+	return l.numBytes
+}
+
+// DeleteAtPosition removes the UTF-8 character at charPos, mirroring
+// Tree.DeleteAtPosition. Unlike the in-memory tree's delete path, this
+// doesn't merge underfull pages back together; it trades some wasted
+// space for not having to rebalance on every delete.
+func (t *PersistentTree) DeleteAtPosition(charPos uint64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	h := t.header()
+	return t.deleteFrom(h.rootOffset, charPos)
+}
+
+func (t *PersistentTree) deleteFrom(offset uint64, charPos uint64) error {
+	page := t.page(offset)
+	if pageKind(page) == persistentKindLeaf {
+		l := decodeLeafPage(page)
+		byteOffset := leafByteOffsetForPosition(l, charPos)
+		if byteOffset >= l.numBytes {
+			return nil
+		}
+		width := uint64(utf8CharWidth[l.text[byteOffset]])
+		copy(l.text[byteOffset:], l.text[byteOffset+width:l.numBytes])
+		l.numBytes -= width
+		return t.writePage(offset, func(page []byte) { encodeLeafPage(page, l) })
+	}
+
+	inner := decodeInnerPage(page)
+	childIdx, adjustedCharPos := locateInnerChild(inner, charPos)
+	if err := t.deleteFrom(inner.children[childIdx], adjustedCharPos); err != nil {
+		return err
+	}
+	inner.keys[childIdx] = t.pageKey(inner.children[childIdx])
+	return t.writePage(offset, func(page []byte) { encodeInnerPage(page, inner) })
+}
+
+// PersistentCursor reads UTF-8 bytes from a PersistentTree, walking the
+// mmap'd leaf page list. It implements io.Reader.
+type PersistentCursor struct {
+	tree           *PersistentTree
+	pageOffset     uint64
+	textByteOffset uint64
+}
+
+func (c *PersistentCursor) Read(b []byte) (int, error) {
+	i := 0
+	for {
+		if i == len(b) {
+			return i, nil
+		}
+		if c.pageOffset == persistentNilPage {
+			return i, io.EOF
+		}
+
+		l := decodeLeafPage(c.tree.page(c.pageOffset))
+		n := copy(b[i:], l.text[c.textByteOffset:l.numBytes])
+		c.textByteOffset += uint64(n)
+		i += n
+
+		if c.textByteOffset == l.numBytes {
+			c.pageOffset = l.next
+			c.textByteOffset = 0
+		}
+	}
+}
+
+// CursorAtPosition returns a cursor starting at the UTF-8 character at
+// charPos, mirroring Tree.CursorAtPosition.
+func (t *PersistentTree) CursorAtPosition(charPos uint64) *PersistentCursor {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	offset := t.header().rootOffset
+	for pageKind(t.page(offset)) == persistentKindInner {
+		inner := decodeInnerPage(t.page(offset))
+		childIdx, adjusted := locateInnerChild(inner, charPos)
+		offset = inner.children[childIdx]
+		charPos = adjusted
+	}
+
+	l := decodeLeafPage(t.page(offset))
+	return &PersistentCursor{tree: t, pageOffset: offset, textByteOffset: leafByteOffsetForPosition(l, charPos)}
+}
@@ -75,8 +75,6 @@ func bulkLoadIntoLeaves(r io.Reader) ([]nodeGroup, error) {
 				} else {
 					newGroup := &leafNodeGroup{numNodes: 1}
 					leafGroups = append(leafGroups, newGroup)
-					newGroup.prev = currentGroup
-					currentGroup.next = newGroup
 					currentGroup = newGroup
 					currentNode = &currentGroup.nodes[0]
 				}
@@ -127,14 +125,52 @@ func buildTreeFromLeaves(leafGroups []nodeGroup) *innerNode {
 
 // DeleteAtPosition removes the UTF-8 character at the specified position (0-indexed).
 // If charPos is past the end of the text, this has no effect.
+// Only the path from the root down to the affected leaf is replaced; every
+// other node is shared with whatever Snapshot the tree had before the call,
+// so a Snapshot taken before DeleteAtPosition keeps reading the old text.
 func (t *Tree) DeleteAtPosition(charPos uint64) {
-	t.root.deleteAtPosition(charPos)
+	newRoot := *t.root
+	newRoot.deleteAtPosition(charPos)
+	t.root = &newRoot
+}
+
+// Snapshot returns an independent Tree representing the text as it exists
+// right now. Because InsertAtPosition and DeleteAtPosition always replace
+// the nodes on the path they touch instead of mutating them (see the
+// nodeGroup doc comment), whatever the snapshot's root points to is
+// unaffected by later edits to t: Snapshot is an O(1) copy that shares every
+// untouched subtree with the live tree. A Cursor built from a Snapshot is
+// therefore safe to read from concurrently with edits to t. There's no
+// separate reclamation step for old versions: once nothing, neither a Tree
+// nor a Cursor built from one, references a node or node group anymore, it's
+// reclaimed by the garbage collector like anything else.
+func (t *Tree) Snapshot() *Tree {
+	root := *t.root
+	return &Tree{root: &root}
 }
 
 // CursorAtPosition returns a cursor starting at the UTF-8 character at the specified position (0-indexed).
 // If the position is past the end of the text, the returned cursor will read zero bytes.
 func (t *Tree) CursorAtPosition(charPos uint64) *Cursor {
-	return t.root.cursorAtPosition(charPos)
+	return cursorAtPosition(t.root, charPos)
+}
+
+// ByteOffsetAtPosition returns the byte offset of the UTF-8 character at
+// the specified position (0-indexed). If charPos is past the end of the
+// text, this returns the total byte length of the text. This is useful for
+// reconciling positions reported by external tools that work in bytes
+// (LSP, grep output) with charPos-based APIs elsewhere in this package,
+// without a full O(n) scan over the text.
+func (t *Tree) ByteOffsetAtPosition(charPos uint64) uint64 {
+	return t.root.byteOffsetAtPosition(charPos)
+}
+
+// CursorAtByteOffset returns a cursor starting at the UTF-8 character
+// whose first byte is at byteOffset. byteOffset must fall on a UTF-8
+// character boundary. If byteOffset is past the end of the text, the
+// returned cursor will read zero bytes.
+func (t *Tree) CursorAtByteOffset(byteOffset uint64) *Cursor {
+	return cursorAtByteOffset(t.root, byteOffset)
 }
 
 // CursorAtLine returns a cursor starting at the first character at the specified line (0-indexed).
@@ -144,21 +180,37 @@ func (t *Tree) CursorAtPosition(charPos uint64) *Cursor {
 func (t *Tree) CursorAtLine(lineNum uint64) *Cursor {
 	if lineNum == 0 {
 		// Special case the first line, since it's the only line that doesn't immediately follow a newline character.
-		return t.root.cursorAtPosition(0)
+		return cursorAtPosition(t.root, 0)
 	}
 
-	return t.root.cursorAfterNewline(lineNum - 1)
+	return cursorAfterNewline(t.root, lineNum-1)
 }
 
 // text.Cursor reads UTF-8 bytes from a text.Tree.
 // It implements io.Reader.
-// text.Tree is NOT thread-safe, so reading from a tree while modifying it is undefined behavior!
+//
+// A Cursor holds the path of groups and node indices from the tree's root
+// down to its current leaf, rather than a pointer to the leaf's "next"
+// sibling: leaf node groups used to form a doubly linked list, but splicing
+// a new sibling into that list meant mutating a neighbor group's prev/next
+// pointers in place, which would corrupt a Snapshot sharing that neighbor.
+// Advancing now walks back up the path to the nearest ancestor with an
+// unvisited child and back down, which only ever touches the path itself.
+// A Cursor built from a Snapshot is safe to read concurrently with edits to
+// the Tree the snapshot was taken from.
 type Cursor struct {
-	group          *leafNodeGroup
-	nodeIdx        uint64
+	path           []cursorPathEntry
 	textByteOffset uint64
 }
 
+// cursorPathEntry records, for one level of the path from the tree's root
+// down to a Cursor's current leaf, which child group that level descended
+// into and which node within it.
+type cursorPathEntry struct {
+	group   nodeGroup
+	nodeIdx uint64
+}
+
 func (c *Cursor) Read(b []byte) (int, error) {
 	i := 0
 	for {
@@ -166,28 +218,191 @@ func (c *Cursor) Read(b []byte) (int, error) {
 			return i, nil
 		}
 
-		if c.group == nil {
+		if len(c.path) == 0 {
 			return i, io.EOF
 		}
 
-		node := &c.group.nodes[c.nodeIdx]
+		last := &c.path[len(c.path)-1]
+		leafGroup := last.group.(*leafNodeGroup)
+		node := &leafGroup.nodes[last.nodeIdx]
+
 		bytesWritten := copy(b[i:], node.textBytes[c.textByteOffset:node.numBytes])
 		c.textByteOffset += uint64(bytesWritten)
 		i += bytesWritten
 
 		if c.textByteOffset == uint64(node.numBytes) {
-			c.nodeIdx++
 			c.textByteOffset = 0
+			c.advance()
 		}
+	}
+}
 
-		if c.nodeIdx == c.group.numNodes {
-			c.group = c.group.next
-			c.nodeIdx = 0
-			c.textByteOffset = 0
+// ReadReverse reads the UTF-8 characters immediately preceding the
+// cursor's current position, moving the cursor backward as it goes:
+// b[0:n] holds those characters in their normal left-to-right byte order,
+// ending with the character immediately before the position the cursor
+// started this call at. Unlike Read, ReadReverse never splits a
+// multi-byte character across two calls: if b has room for only part of
+// the next character, it stops there rather than writing a partial
+// character. This is what a backward regex search uses to scan without
+// first seeking to the start of the tree.
+func (c *Cursor) ReadReverse(b []byte) (int, error) {
+	// Characters are discovered in right-to-left order but must end up in
+	// b[0:n] in normal left-to-right order, so each one is written just
+	// before the ones already collected, working in from the end of b;
+	// the filled region is shifted down to b[0:n] right before returning.
+	pos := len(b)
+	for {
+		if len(c.path) == 0 {
+			n := copy(b, b[pos:])
+			return n, io.EOF
+		}
+
+		last := &c.path[len(c.path)-1]
+		leafGroup := last.group.(*leafNodeGroup)
+		node := &leafGroup.nodes[last.nodeIdx]
+
+		if c.textByteOffset == 0 {
+			c.retreat()
+			if len(c.path) == 0 {
+				n := copy(b, b[pos:])
+				return n, io.EOF
+			}
+			last = &c.path[len(c.path)-1]
+			leafGroup = last.group.(*leafNodeGroup)
+			node = &leafGroup.nodes[last.nodeIdx]
+			c.textByteOffset = uint64(node.numBytes)
 		}
+
+		startByte := node.byteOffsetOfPrevChar(c.textByteOffset)
+		charWidth := int(c.textByteOffset - startByte)
+		if charWidth > pos {
+			n := copy(b, b[pos:])
+			return n, nil
+		}
+
+		pos -= charWidth
+		copy(b[pos:pos+charWidth], node.textBytes[startByte:c.textByteOffset])
+		c.textByteOffset = startByte
 	}
+}
 
-	return 0, nil
+// retreat moves the cursor to the previous leaf node in left-to-right
+// order: the mirror image of advance. It backtracks up the path to the
+// nearest ancestor with an unvisited earlier sibling, then descends into
+// that sibling's rightmost leaf.
+func (c *Cursor) retreat() {
+	for len(c.path) > 0 {
+		last := &c.path[len(c.path)-1]
+		if last.nodeIdx > 0 {
+			last.nodeIdx--
+			c.descendToRightmostLeaf()
+			return
+		}
+		c.path = c.path[:len(c.path)-1]
+	}
+}
+
+func (c *Cursor) descendToRightmostLeaf() {
+	for {
+		last := &c.path[len(c.path)-1]
+		innerGroup, ok := last.group.(*innerNodeGroup)
+		if !ok {
+			return
+		}
+		child := innerGroup.nodes[last.nodeIdx].child
+		c.path = append(c.path, cursorPathEntry{group: child, nodeIdx: child.count() - 1})
+	}
+}
+
+// advance moves the cursor to the next leaf node in left-to-right order:
+// it backtracks up the path to the nearest ancestor with an unvisited
+// sibling, then descends into that sibling's leftmost leaf. When the path
+// empties, the cursor has read every leaf in the tree.
+func (c *Cursor) advance() {
+	for len(c.path) > 0 {
+		last := &c.path[len(c.path)-1]
+		last.nodeIdx++
+		if last.nodeIdx < last.group.count() {
+			c.descendToLeftmostLeaf()
+			return
+		}
+		c.path = c.path[:len(c.path)-1]
+	}
+}
+
+func (c *Cursor) descendToLeftmostLeaf() {
+	for {
+		last := &c.path[len(c.path)-1]
+		innerGroup, ok := last.group.(*innerNodeGroup)
+		if !ok {
+			return
+		}
+		child := innerGroup.nodes[last.nodeIdx].child
+		c.path = append(c.path, cursorPathEntry{group: child, nodeIdx: 0})
+	}
+}
+
+func cursorAtPosition(root *innerNode, charPos uint64) *Cursor {
+	path := make([]cursorPathEntry, 0, 1)
+	n := root
+	for {
+		nodeIdx, adjustedCharPos := n.locatePosition(charPos)
+		path = append(path, cursorPathEntry{group: n.child, nodeIdx: nodeIdx})
+		charPos = adjustedCharPos
+
+		switch g := n.child.(type) {
+		case *innerNodeGroup:
+			n = &g.nodes[nodeIdx]
+		case *leafNodeGroup:
+			textByteOffset := g.nodes[nodeIdx].byteOffsetForPosition(charPos)
+			return &Cursor{path: path, textByteOffset: textByteOffset}
+		}
+	}
+}
+
+func cursorAfterNewline(root *innerNode, newlinePos uint64) *Cursor {
+	path := make([]cursorPathEntry, 0, 1)
+	n := root
+	for {
+		nodeIdx := uint64(keySearch(n.cumNewlines[:], int(n.numKeys), uint32(newlinePos)))
+
+		var c uint64
+		if nodeIdx > 0 {
+			c = uint64(n.cumNewlines[nodeIdx-1])
+		}
+		newlinePos -= c
+		path = append(path, cursorPathEntry{group: n.child, nodeIdx: nodeIdx})
+
+		switch g := n.child.(type) {
+		case *innerNodeGroup:
+			n = &g.nodes[nodeIdx]
+		case *leafNodeGroup:
+			textByteOffset := g.nodes[nodeIdx].byteOffsetAfterNewline(newlinePos)
+			return &Cursor{path: path, textByteOffset: textByteOffset}
+		}
+	}
+}
+
+func cursorAtByteOffset(root *innerNode, byteOffset uint64) *Cursor {
+	if total := root.key().numBytes; byteOffset > total {
+		byteOffset = total
+	}
+
+	path := make([]cursorPathEntry, 0, 1)
+	n := root
+	for {
+		nodeIdx, adjustedByteOffset := n.locateByteOffset(byteOffset)
+		path = append(path, cursorPathEntry{group: n.child, nodeIdx: nodeIdx})
+		byteOffset = adjustedByteOffset
+
+		if g, ok := n.child.(*innerNodeGroup); ok {
+			n = &g.nodes[nodeIdx]
+			continue
+		}
+
+		return &Cursor{path: path, textByteOffset: byteOffset}
+	}
 }
 
 const maxKeysPerNode = 64
@@ -195,14 +410,39 @@ const maxNodesPerGroup = maxKeysPerNode
 const maxBytesPerLeaf = 63
 
 // nodeGroup is either an inner node group or a leaf node group.
+//
+// Every mutating method clones the receiver before changing anything and
+// returns the clone, rather than mutating the receiver in place: this is
+// what lets Tree.Snapshot hand out an old root that still points at the
+// original, untouched groups after the live tree goes on to edit them. A
+// node one level up is always already a private copy by the time it calls
+// down into nodeGroup (see innerNode.deleteAtPosition/insertAtPosition), so
+// it's safe for it to just overwrite its own child pointer with whatever
+// newGroup comes back.
 type nodeGroup interface {
 	keys() []indexKey
-	deleteAtPosition(nodeIdx uint64, charPos uint64) (didDelete, wasNewline bool)
-	cursorAtPosition(nodeIdx uint64, charPos uint64) *Cursor
-	cursorAfterNewline(nodeIdx uint64, newlinePos uint64) *Cursor
+
+	// count reports how many nodes are populated in this group, for
+	// Cursor.advance to find the end of a group without a sentinel.
+	count() uint64
+
+	deleteAtPosition(nodeIdx uint64, charPos uint64) (newGroup nodeGroup, didDelete, wasNewline bool)
+
+	// byteOffsetAtPosition returns the byte offset of the UTF-8 character
+	// at charPos within the node at nodeIdx.
+	byteOffsetAtPosition(nodeIdx uint64, charPos uint64) uint64
+
+	// insertAtPosition inserts s (at most maxInsertChunkBytes bytes) into the
+	// node at nodeIdx of a clone of the receiver, returning that clone. If
+	// the insert overflows the node and there's no room left in the clone
+	// for another node, the clone splits in half (the lower half is kept as
+	// newGroup) and insertAtPosition also returns the new upper-half sibling
+	// group for the caller to link in as a new node one level up; otherwise
+	// splitGroup is nil.
+	insertAtPosition(nodeIdx uint64, charPos uint64, s string) (newGroup, splitGroup nodeGroup)
 }
 
-// indexKey is used to navigate from an inner node to the child node containing a particular line or character offset.
+// indexKey is used to navigate from an inner node to the child node containing a particular line, character offset, or byte offset.
 type indexKey struct {
 
 	// Number of UTF-8 characters in a subtree.
@@ -210,6 +450,9 @@ type indexKey struct {
 
 	// Number of newline characters in a subtree.
 	numNewlines uint64
+
+	// Number of bytes in a subtree.
+	numBytes uint64
 }
 
 // innerNodeGroup is a group of inner nodes referenced by a parent inner node.
@@ -226,16 +469,26 @@ func (g *innerNodeGroup) keys() []indexKey {
 	return keys
 }
 
-func (g *innerNodeGroup) deleteAtPosition(nodeIdx uint64, charPos uint64) (didDelete, wasNewline bool) {
-	return g.nodes[nodeIdx].deleteAtPosition(charPos)
+func (g *innerNodeGroup) count() uint64 {
+	return g.numNodes
+}
+
+func (g *innerNodeGroup) clone() *innerNodeGroup {
+	clone := *g
+	return &clone
 }
 
-func (g *innerNodeGroup) cursorAtPosition(nodeIdx uint64, charPos uint64) *Cursor {
-	return g.nodes[nodeIdx].cursorAtPosition(charPos)
+func (g *innerNodeGroup) deleteAtPosition(nodeIdx uint64, charPos uint64) (nodeGroup, bool, bool) {
+	newGroup := g.clone()
+	didDelete, wasNewline := newGroup.nodes[nodeIdx].deleteAtPosition(charPos)
+	if !didDelete {
+		return g, false, false
+	}
+	return newGroup, true, wasNewline
 }
 
-func (g *innerNodeGroup) cursorAfterNewline(nodeIdx uint64, newlinePos uint64) *Cursor {
-	return g.nodes[nodeIdx].cursorAfterNewline(newlinePos)
+func (g *innerNodeGroup) byteOffsetAtPosition(nodeIdx uint64, charPos uint64) uint64 {
+	return g.nodes[nodeIdx].byteOffsetAtPosition(charPos)
 }
 
 // innerNode is used to navigate to the leaf node containing a character offset or line number.
@@ -251,6 +504,15 @@ type innerNode struct {
 
 	// Each key corresponds to a node in the child group.
 	keys [maxKeysPerNode]indexKey
+
+	// cumChars[i], cumNewlines[i], and cumBytes[i] are the inclusive running
+	// totals of keys[0..i].numChars / numNewlines / numBytes. Keeping them
+	// as contiguous uint32 arrays (rather than re-summing keys on every
+	// lookup) is what makes locatePosition, cursorAfterNewline, and
+	// locateByteOffset vectorizable: see keySearch.
+	cumChars    [maxKeysPerNode]uint32
+	cumNewlines [maxKeysPerNode]uint32
+	cumBytes    [maxKeysPerNode]uint32
 }
 
 func (n *innerNode) key() indexKey {
@@ -259,65 +521,99 @@ func (n *innerNode) key() indexKey {
 		key := n.keys[i]
 		nodeKey.numChars += key.numChars
 		nodeKey.numNewlines += key.numNewlines
+		nodeKey.numBytes += key.numBytes
 	}
 	return nodeKey
 }
 
 func (n *innerNode) recalculateChildKeys() {
 	childKeys := n.child.keys()
+	var cumChars, cumNewlines, cumBytes uint64
 	for i, key := range childKeys {
 		n.keys[i] = key
+		cumChars += key.numChars
+		cumNewlines += key.numNewlines
+		cumBytes += key.numBytes
+		n.cumChars[i] = uint32(cumChars)
+		n.cumNewlines[i] = uint32(cumNewlines)
+		n.cumBytes[i] = uint32(cumBytes)
 	}
 	n.numKeys = uint64(len(childKeys))
 }
 
 func (n *innerNode) deleteAtPosition(charPos uint64) (didDelete, wasNewline bool) {
 	nodeIdx, adjustedCharPos := n.locatePosition(charPos)
-	didDelete, wasNewline = n.child.deleteAtPosition(nodeIdx, adjustedCharPos)
+	newChild, didDelete, wasNewline := n.child.deleteAtPosition(nodeIdx, adjustedCharPos)
 	if didDelete {
-		n.keys[nodeIdx].numChars--
-		if wasNewline {
-			n.keys[nodeIdx].numNewlines--
-		}
+		n.child = newChild
+		// A full recalculation (rather than patching n.keys[nodeIdx] and
+		// the cumulative arrays in place) is required here because the
+		// delete may have merged two underfull children in n.child into
+		// one, which changes how many entries n.child.keys() returns.
+		n.recalculateChildKeys()
 	}
 	return
 }
 
-func (n *innerNode) cursorAtPosition(charPos uint64) *Cursor {
+func (n *innerNode) byteOffsetAtPosition(charPos uint64) uint64 {
 	nodeIdx, adjustedCharPos := n.locatePosition(charPos)
-	return n.child.cursorAtPosition(nodeIdx, adjustedCharPos)
+
+	var byteOffset uint64
+	if nodeIdx > 0 {
+		byteOffset = uint64(n.cumBytes[nodeIdx-1])
+	}
+
+	return byteOffset + n.child.byteOffsetAtPosition(nodeIdx, adjustedCharPos)
 }
 
-func (n *innerNode) cursorAfterNewline(newlinePos uint64) *Cursor {
-	c := uint64(0)
-	for i := uint64(0); i < n.numKeys-1; i++ {
-		nc := n.keys[i].numNewlines
-		if newlinePos < c+nc {
-			return n.child.cursorAfterNewline(i, newlinePos-c)
+func (n *innerNode) locateByteOffset(byteOffset uint64) (nodeIdx, adjustedByteOffset uint64) {
+	// byteOffset past the end of this subtree lands in the last child,
+	// offset from the start of that child rather than from the start of
+	// the subtree, mirroring locatePosition.
+	if total := uint64(n.cumBytes[n.numKeys-1]); byteOffset >= total {
+		idx := n.numKeys - 1
+		var c uint64
+		if idx > 0 {
+			c = uint64(n.cumBytes[idx-1])
 		}
-		c += nc
+		return idx, byteOffset - c
 	}
 
-	return n.child.cursorAfterNewline(n.numKeys-1, newlinePos-c)
+	idx := keySearch(n.cumBytes[:], int(n.numKeys), uint32(byteOffset))
+
+	var c uint64
+	if idx > 0 {
+		c = uint64(n.cumBytes[idx-1])
+	}
+
+	return uint64(idx), byteOffset - c
 }
 
 func (n *innerNode) locatePosition(charPos uint64) (nodeIdx, adjustedCharPos uint64) {
-	c := uint64(0)
-	for i := uint64(0); i < n.numKeys; i++ {
-		nc := n.keys[i].numChars
-		if charPos < c+nc {
-			return i, charPos - c
+	// charPos past the end of this subtree (e.g. an append at the very end
+	// of the tree) lands in the last child, offset from the start of that
+	// child rather than from the start of the subtree.
+	if total := uint64(n.cumChars[n.numKeys-1]); charPos >= total {
+		idx := n.numKeys - 1
+		var c uint64
+		if idx > 0 {
+			c = uint64(n.cumChars[idx-1])
 		}
-		c += nc
+		return idx, charPos - c
 	}
-	return n.numKeys - 1, c
+
+	idx := keySearch(n.cumChars[:], int(n.numKeys), uint32(charPos))
+
+	var c uint64
+	if idx > 0 {
+		c = uint64(n.cumChars[idx-1])
+	}
+
+	return uint64(idx), charPos - c
 }
 
 // leafNodeGroup is a group of leaf nodes referenced by an inner node.
-// These form a doubly-linked list so a cursor can scan the text efficiently.
 type leafNodeGroup struct {
-	prev     *leafNodeGroup
-	next     *leafNodeGroup
 	numNodes uint64
 	nodes    [maxNodesPerGroup]leafNode
 }
@@ -330,28 +626,27 @@ func (g *leafNodeGroup) keys() []indexKey {
 	return keys
 }
 
-func (g *leafNodeGroup) deleteAtPosition(nodeIdx uint64, charPos uint64) (didDelete, wasNewline bool) {
-	// Don't bother rebalancing the tree.  This leaves extra space in the leaves,
-	// but that's okay because usually the user will want to insert more text anyway.
-	return g.nodes[nodeIdx].deleteAtPosition(charPos)
+func (g *leafNodeGroup) count() uint64 {
+	return g.numNodes
 }
 
-func (g *leafNodeGroup) cursorAtPosition(nodeIdx uint64, charPos uint64) *Cursor {
-	textByteOffset := g.nodes[nodeIdx].byteOffsetForPosition(charPos)
-	return &Cursor{
-		group:          g,
-		nodeIdx:        nodeIdx,
-		textByteOffset: textByteOffset,
-	}
+func (g *leafNodeGroup) clone() *leafNodeGroup {
+	clone := *g
+	return &clone
 }
 
-func (g *leafNodeGroup) cursorAfterNewline(nodeIdx uint64, newlinePos uint64) *Cursor {
-	textByteOffset := g.nodes[nodeIdx].byteOffsetAfterNewline(newlinePos)
-	return &Cursor{
-		group:          g,
-		nodeIdx:        nodeIdx,
-		textByteOffset: textByteOffset,
+func (g *leafNodeGroup) deleteAtPosition(nodeIdx uint64, charPos uint64) (nodeGroup, bool, bool) {
+	newGroup := g.clone()
+	didDelete, wasNewline := newGroup.nodes[nodeIdx].deleteAtPosition(charPos)
+	if !didDelete {
+		return g, false, false
 	}
+	newGroup.mergeIfUnderfull(nodeIdx)
+	return newGroup, true, wasNewline
+}
+
+func (g *leafNodeGroup) byteOffsetAtPosition(nodeIdx uint64, charPos uint64) uint64 {
+	return g.nodes[nodeIdx].byteOffsetForPosition(charPos)
 }
 
 // leafNode is a node that stores UTF-8 text as a byte array.
@@ -369,7 +664,7 @@ type leafNode struct {
 }
 
 func (l *leafNode) key() indexKey {
-	key := indexKey{}
+	key := indexKey{numBytes: uint64(l.numBytes)}
 	for _, b := range l.textBytes[:l.numBytes] {
 		key.numChars += uint64(utf8StartByteIndicator[b])
 		if b == '\n' {
@@ -406,6 +701,20 @@ func (l *leafNode) byteOffsetForPosition(charPos uint64) uint64 {
 	return uint64(l.numBytes)
 }
 
+// byteOffsetOfPrevChar returns the byte offset of the start of the
+// character immediately before byteOffset within this leaf, by scanning
+// backward for a UTF-8 start byte. byteOffset must be greater than 0. The
+// scan stops at index 0 even if no start byte is found there, so a leaf
+// that (contrary to the invariant that a leaf never starts mid-rune) begins
+// with a continuation byte can't underflow i.
+func (l *leafNode) byteOffsetOfPrevChar(byteOffset uint64) uint64 {
+	i := byteOffset - 1
+	for i > 0 && utf8StartByteIndicator[l.textBytes[i]] == 0 {
+		i--
+	}
+	return i
+}
+
 func (l *leafNode) byteOffsetAfterNewline(newlinePos uint64) uint64 {
 	n := uint64(0)
 	for i, b := range l.textBytes[:l.numBytes] {
@@ -0,0 +1,80 @@
+package text
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// FuzzTreeInsertDelete alternates random inserts and deletes against a Tree
+// and a plain []rune model of the same text, failing as soon as the two
+// diverge. This exercises the COW leaf-split/merge paths in insert.go and
+// tree.go (mergeIfUnderfull, mergeOrRedistribute, splitAtCharBoundary)
+// against the kind of maximally-packed, multi-byte-rune-straddling leaves
+// that a hand-written table test is unlikely to hit.
+func FuzzTreeInsertDelete(f *testing.F) {
+	f.Add("hello, world\nsecond line\n", []byte{0, 3, 'X', 1, 2, 0, 0, 'y'})
+	f.Add("日本語のテキスト\n", []byte{1, 0, 0, 1, 1, 2})
+
+	f.Fuzz(func(t *testing.T, orig string, ops []byte) {
+		if !utf8.ValidString(orig) {
+			t.Skip()
+		}
+
+		tree, err := NewTreeFromString(orig)
+		if err != nil {
+			t.Fatalf("NewTreeFromString: %v", err)
+		}
+		model := []rune(orig)
+
+		for i := 0; i+1 < len(ops); {
+			op := ops[i]
+			i++
+
+			switch op % 2 {
+			case 0: // insert a single rune
+				if i+1 >= len(ops) {
+					continue
+				}
+				pos := uint64(ops[i]) % uint64(len(model)+1)
+				r := rune(ops[i+1])
+				i += 2
+
+				if err := tree.InsertAtPosition(pos, string(r)); err != nil {
+					t.Fatalf("InsertAtPosition(%d, %q): %v", pos, r, err)
+				}
+				model = append(model[:pos:pos], append([]rune{r}, model[pos:]...)...)
+
+			case 1: // delete a single rune
+				if len(model) == 0 {
+					continue
+				}
+				pos := uint64(ops[i]) % uint64(len(model))
+				i++
+
+				tree.DeleteAtPosition(pos)
+				model = append(model[:pos:pos], model[pos+1:]...)
+			}
+
+			if got, want := readAllText(tree), string(model); got != want {
+				t.Fatalf("after op: got %q, want %q", got, want)
+			}
+		}
+	})
+}
+
+// readAllText reads the full contents of a Tree via its Cursor, as a
+// reference implementation independent of the tree's internal layout.
+func readAllText(tree *Tree) string {
+	var sb strings.Builder
+	c := tree.CursorAtPosition(0)
+	var buf [64]byte
+	for {
+		n, err := c.Read(buf[:])
+		sb.Write(buf[:n])
+		if err != nil {
+			break
+		}
+	}
+	return sb.String()
+}
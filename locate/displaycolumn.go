@@ -0,0 +1,78 @@
+package locate
+
+import (
+	"io"
+
+	"github.com/aretext/aretext/text"
+	"github.com/aretext/aretext/text/segment"
+)
+
+// DisplayColumn returns the visual column of pos within its line: the
+// number of terminal cells occupied by the grapheme clusters between the
+// start of the line and pos, expanding tabs to tabSize and counting East
+// Asian Wide/Fullwidth clusters as two cells and zero-width clusters as
+// zero. This is the column a status bar or a "go to column" motion should
+// report, since a plain rune count drifts from the cursor's actual
+// on-screen position for CJK, combining marks, and ZWJ emoji sequences.
+func DisplayColumn(tree *text.Tree, pos uint64, tabSize int) int {
+	lineStartPos := tree.LineStartPosition(tree.LineNumForPosition(pos))
+	targetOffset := pos - lineStartPos
+
+	reader := tree.ReaderAtPosition(lineStartPos)
+	gcIter := segment.NewGraphemeClusterIter(reader)
+	seg := segment.Empty()
+
+	var col, offset uint64
+	for offset < targetOffset {
+		err := gcIter.NextSegment(seg)
+		if err == io.EOF || seg.HasNewline() {
+			break
+		}
+		runes := seg.Runes()
+		col += segment.GraphemeClusterWidth(runes, col, uint64(tabSize))
+		offset += uint64(len(runes))
+	}
+
+	return int(col)
+}
+
+// PositionFromDisplayColumn returns the position on pos's line whose visual
+// column (see DisplayColumn) is closest to targetCol without exceeding it.
+// If targetCol is past the end of the line, it returns the position just
+// after the last character on the line. This is the inverse of DisplayColumn, for
+// motions like "go to column N" or preserving a visual column across a
+// vertical cursor move, both of which should land on the same on-screen
+// column rather than the same rune offset.
+func PositionFromDisplayColumn(tree *text.Tree, pos uint64, targetCol int, tabSize int) uint64 {
+	lineStartPos := tree.LineStartPosition(tree.LineNumForPosition(pos))
+
+	reader := tree.ReaderAtPosition(lineStartPos)
+	gcIter := segment.NewGraphemeClusterIter(reader)
+	seg := segment.Empty()
+
+	target := uint64(0)
+	if targetCol > 0 {
+		target = uint64(targetCol)
+	}
+
+	result := lineStartPos
+	var col, offset uint64
+	for {
+		err := gcIter.NextSegment(seg)
+		if err == io.EOF || seg.HasNewline() {
+			break
+		}
+
+		runes := seg.Runes()
+		width := segment.GraphemeClusterWidth(runes, col, uint64(tabSize))
+		if col+width > target {
+			break
+		}
+
+		col += width
+		offset += uint64(len(runes))
+		result = lineStartPos + offset
+	}
+
+	return result
+}
@@ -0,0 +1,57 @@
+package locate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aretext/aretext/text"
+)
+
+func TestDisplayColumn(t *testing.T) {
+	testCases := []struct {
+		name        string
+		inputString string
+		pos         uint64
+		tabSize     int
+		expectedCol int
+	}{
+		{
+			name:        "ascii, no tabs",
+			inputString: "abcdef",
+			pos:         3,
+			tabSize:     4,
+			expectedCol: 3,
+		},
+		{
+			name:        "tab expands to next stop",
+			inputString: "ab\tcd",
+			pos:         4,
+			tabSize:     4,
+			expectedCol: 5,
+		},
+		{
+			name:        "east asian wide runes count double",
+			inputString: "日本語",
+			pos:         2,
+			tabSize:     4,
+			expectedCol: 4,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			textTree, err := text.NewTreeFromString(tc.inputString)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedCol, DisplayColumn(textTree, tc.pos, tc.tabSize))
+		})
+	}
+}
+
+func TestPositionFromDisplayColumn(t *testing.T) {
+	textTree, err := text.NewTreeFromString("abcdef")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), PositionFromDisplayColumn(textTree, 0, 3, 4))
+	assert.Equal(t, uint64(6), PositionFromDisplayColumn(textTree, 0, 100, 4))
+}
@@ -0,0 +1,105 @@
+package locate
+
+import (
+	"io"
+	"unicode"
+
+	"github.com/aretext/aretext/text"
+)
+
+// lineIsBlank reports whether the line starting at lineStartPos contains
+// only whitespace before the next newline or the end of the document.
+func lineIsBlank(tree *text.Tree, lineStartPos uint64) bool {
+	reader := tree.ReaderAtPosition(lineStartPos)
+	for {
+		r, _, err := reader.ReadRune()
+		if err == io.EOF || r == '\n' {
+			return true
+		} else if !unicode.IsSpace(r) {
+			return false
+		}
+	}
+}
+
+// paragraphLineRun finds the [startLine, endLine) run of lines starting at
+// lineNum that share the same blank-or-non-blank status as lineNum.
+func paragraphLineRun(tree *text.Tree, lineNum uint64) (startLine, endLine uint64) {
+	numLines := tree.NumLines()
+	blank := lineIsBlank(tree, tree.LineStartPosition(lineNum))
+
+	startLine = lineNum
+	for startLine > 0 && lineIsBlank(tree, tree.LineStartPosition(startLine-1)) == blank {
+		startLine--
+	}
+
+	endLine = lineNum + 1
+	for endLine < numLines && lineIsBlank(tree, tree.LineStartPosition(endLine)) == blank {
+		endLine++
+	}
+
+	return startLine, endLine
+}
+
+// InnerParagraphObject returns the start and end positions of the paragraph
+// (or paragraphs, for count > 1) under the cursor, excluding the blank lines
+// that separate it from neighboring paragraphs.
+//
+// A paragraph is a maximal run of non-blank lines, where a blank line
+// contains only whitespace (or nothing) up to the end of the line.
+// If the cursor is on a blank line, the "inner paragraph" is the empty
+// range at the cursor, mirroring how InnerWordObject treats an empty line.
+func InnerParagraphObject(tree *text.Tree, pos uint64, count uint64) (uint64, uint64) {
+	if count == 0 {
+		count = 1
+	}
+
+	lineNum := tree.LineNumForPosition(pos)
+	if lineIsBlank(tree, tree.LineStartPosition(lineNum)) {
+		return pos, pos
+	}
+
+	startLine, endLine := paragraphLineRun(tree, lineNum)
+	for i := uint64(1); i < count && endLine < tree.NumLines(); i++ {
+		// Skip the blank-line run separating this paragraph from the next one,
+		// then extend through the next non-blank run.
+		_, nextNonBlankStart := paragraphLineRun(tree, endLine)
+		if nextNonBlankStart >= tree.NumLines() {
+			break
+		}
+		_, endLine = paragraphLineRun(tree, nextNonBlankStart)
+	}
+
+	startPos := tree.LineStartPosition(startLine)
+	endPos := tree.LineStartPosition(endLine)
+	return startPos, endPos
+}
+
+// ParagraphObject returns the start and end positions of "a paragraph": the
+// same run as InnerParagraphObject, plus the blank lines that trail it, or
+// (if there are no trailing blank lines because the paragraph ends at the
+// end of the document) the blank lines that lead into it.
+func ParagraphObject(tree *text.Tree, pos uint64, count uint64) (uint64, uint64) {
+	startPos, endPos := InnerParagraphObject(tree, pos, count)
+	if startPos == endPos {
+		return startPos, endPos
+	}
+
+	numLines := tree.NumLines()
+	endLine := tree.LineNumForPosition(endPos)
+	if endLine < numLines && lineIsBlank(tree, tree.LineStartPosition(endLine)) {
+		// The empty line after a final newline adds no characters, so it
+		// doesn't count as a trailing blank line.
+		_, afterBlankLine := paragraphLineRun(tree, endLine)
+		if afterBlankPos := tree.LineStartPosition(afterBlankLine); afterBlankPos > endPos {
+			return startPos, afterBlankPos
+		}
+	}
+
+	startLine := tree.LineNumForPosition(startPos)
+	if startLine > 0 && lineIsBlank(tree, tree.LineStartPosition(startLine-1)) {
+		blankStart, _ := paragraphLineRun(tree, startLine-1)
+		startPos = tree.LineStartPosition(blankStart)
+	}
+
+	return startPos, endPos
+}
@@ -0,0 +1,110 @@
+package locate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aretext/aretext/text"
+)
+
+func TestInnerParagraphObject(t *testing.T) {
+	testCases := []struct {
+		name             string
+		inputString      string
+		pos              uint64
+		count            uint64
+		expectedStartPos uint64
+		expectedEndPos   uint64
+	}{
+		{
+			name:             "empty",
+			inputString:      "",
+			pos:              0,
+			count:            1,
+			expectedStartPos: 0,
+			expectedEndPos:   0,
+		},
+		{
+			name:             "single paragraph",
+			inputString:      "abc\ndef\nghi",
+			pos:              4,
+			count:            1,
+			expectedStartPos: 0,
+			expectedEndPos:   11,
+		},
+		{
+			name:             "first of two paragraphs",
+			inputString:      "abc\ndef\n\nghi\n",
+			pos:              0,
+			count:            1,
+			expectedStartPos: 0,
+			expectedEndPos:   8,
+		},
+		{
+			name:             "second of two paragraphs",
+			inputString:      "abc\ndef\n\nghi\n",
+			pos:              9,
+			count:            1,
+			expectedStartPos: 9,
+			expectedEndPos:   13,
+		},
+		{
+			name:             "cursor on blank line",
+			inputString:      "abc\n\ndef\n",
+			pos:              4,
+			count:            1,
+			expectedStartPos: 4,
+			expectedEndPos:   4,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			textTree, err := text.NewTreeFromString(tc.inputString)
+			require.NoError(t, err)
+			startPos, endPos := InnerParagraphObject(textTree, tc.pos, tc.count)
+			assert.Equal(t, tc.expectedStartPos, startPos)
+			assert.Equal(t, tc.expectedEndPos, endPos)
+		})
+	}
+}
+
+func TestParagraphObject(t *testing.T) {
+	testCases := []struct {
+		name             string
+		inputString      string
+		pos              uint64
+		count            uint64
+		expectedStartPos uint64
+		expectedEndPos   uint64
+	}{
+		{
+			name:             "paragraph with trailing blank line",
+			inputString:      "abc\ndef\n\nghi\n",
+			pos:              0,
+			count:            1,
+			expectedStartPos: 0,
+			expectedEndPos:   9,
+		},
+		{
+			name:             "last paragraph with leading blank line",
+			inputString:      "abc\n\ndef\n",
+			pos:              6,
+			count:            1,
+			expectedStartPos: 4,
+			expectedEndPos:   9,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			textTree, err := text.NewTreeFromString(tc.inputString)
+			require.NoError(t, err)
+			startPos, endPos := ParagraphObject(textTree, tc.pos, tc.count)
+			assert.Equal(t, tc.expectedStartPos, startPos)
+			assert.Equal(t, tc.expectedEndPos, endPos)
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package locate
+
+import (
+	"io"
+	"unicode"
+
+	"github.com/aretext/aretext/text"
+)
+
+// sentenceEndPunct reports whether r is one of the runes that can end a sentence.
+func sentenceEndPunct(r rune) bool {
+	return r == '.' || r == '!' || r == '?'
+}
+
+// sentenceTrailingRune reports whether r can appear between sentence-ending
+// punctuation and the whitespace/EOL that confirms the sentence boundary,
+// such as a closing quote or parenthesis.
+func sentenceTrailingRune(r rune) bool {
+	switch r {
+	case '"', '\'', ')', ']', '”', '’':
+		return true
+	default:
+		return false
+	}
+}
+
+// sentenceBoundaries scans the whole document and returns the positions
+// immediately after each sentence-ending sequence (for example, the
+// position right after ". " in "One. Two."). The final boundary is always
+// the length of the document, so every position in the document falls
+// within some [boundaries[i-1], boundaries[i]) sentence range.
+func sentenceBoundaries(tree *text.Tree) []uint64 {
+	reader := tree.ReaderAtPosition(0)
+	boundaries := make([]uint64, 0, 64)
+
+	var pos uint64
+	var sawEndPunct bool
+	for {
+		r, _, err := reader.ReadRune()
+		if err == io.EOF {
+			break
+		}
+
+		if sawEndPunct {
+			if sentenceTrailingRune(r) {
+				// Still within the trailing punctuation after end-of-sentence punctuation.
+			} else if unicode.IsSpace(r) || r == '\n' {
+				boundaries = append(boundaries, pos+1)
+				sawEndPunct = false
+			} else {
+				sawEndPunct = false
+			}
+		} else if sentenceEndPunct(r) {
+			sawEndPunct = true
+		}
+
+		pos++
+	}
+
+	boundaries = append(boundaries, pos)
+	return boundaries
+}
+
+// sentenceRangeContaining returns the [start, end) range of the sentence
+// containing pos, given the document's sentence boundaries.
+func sentenceRangeContaining(boundaries []uint64, pos uint64) (start, end uint64) {
+	for i, b := range boundaries {
+		if pos < b {
+			if i == 0 {
+				return 0, b
+			}
+			return boundaries[i-1], b
+		}
+	}
+	if len(boundaries) == 0 {
+		return 0, 0
+	}
+	return boundaries[len(boundaries)-1], boundaries[len(boundaries)-1]
+}
+
+// InnerSentenceObject returns the start and end positions of the sentence
+// (or count consecutive sentences) under the cursor, trimming leading and
+// trailing whitespace from the range.
+func InnerSentenceObject(tree *text.Tree, pos uint64, count uint64) (uint64, uint64) {
+	if count == 0 {
+		count = 1
+	}
+
+	boundaries := sentenceBoundaries(tree)
+	start, end := sentenceRangeContaining(boundaries, pos)
+	for i := uint64(1); i < count; i++ {
+		if end >= tree.NumChars() {
+			break
+		}
+		_, nextEnd := sentenceRangeContaining(boundaries, end)
+		end = nextEnd
+	}
+
+	start = trimLeadingWhitespace(tree, start, end)
+	end = trimTrailingWhitespace(tree, start, end)
+	return start, end
+}
+
+// ASentenceObject returns the start and end positions of "a sentence": the
+// same range as InnerSentenceObject, plus the whitespace trailing it up to
+// the start of the next sentence.
+func ASentenceObject(tree *text.Tree, pos uint64, count uint64) (uint64, uint64) {
+	if count == 0 {
+		count = 1
+	}
+
+	boundaries := sentenceBoundaries(tree)
+	start, end := sentenceRangeContaining(boundaries, pos)
+	for i := uint64(1); i < count; i++ {
+		if end >= tree.NumChars() {
+			break
+		}
+		_, nextEnd := sentenceRangeContaining(boundaries, end)
+		end = nextEnd
+	}
+
+	start = trimLeadingWhitespace(tree, start, end)
+	return start, end
+}
+
+// trimLeadingWhitespace advances start past leading whitespace runes, up to end.
+func trimLeadingWhitespace(tree *text.Tree, start, end uint64) uint64 {
+	reader := tree.ReaderAtPosition(start)
+	pos := start
+	for pos < end {
+		r, _, err := reader.ReadRune()
+		if err != nil || !unicode.IsSpace(r) {
+			break
+		}
+		pos++
+	}
+	return pos
+}
+
+// trimTrailingWhitespace retreats end past trailing whitespace runes, down to start.
+func trimTrailingWhitespace(tree *text.Tree, start, end uint64) uint64 {
+	for end > start {
+		reader := tree.ReaderAtPosition(end - 1)
+		r, _, err := reader.ReadRune()
+		if err != nil || !unicode.IsSpace(r) {
+			break
+		}
+		end--
+	}
+	return end
+}
@@ -0,0 +1,67 @@
+package locate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aretext/aretext/text"
+)
+
+func TestInnerSentenceObject(t *testing.T) {
+	testCases := []struct {
+		name             string
+		inputString      string
+		pos              uint64
+		expectedStartPos uint64
+		expectedEndPos   uint64
+	}{
+		{
+			name:             "empty",
+			inputString:      "",
+			pos:              0,
+			expectedStartPos: 0,
+			expectedEndPos:   0,
+		},
+		{
+			name:             "first of two sentences",
+			inputString:      "One. Two.",
+			pos:              0,
+			expectedStartPos: 0,
+			expectedEndPos:   4,
+		},
+		{
+			name:             "second of two sentences",
+			inputString:      "One. Two.",
+			pos:              5,
+			expectedStartPos: 5,
+			expectedEndPos:   9,
+		},
+		{
+			name:             "sentence ending in quote",
+			inputString:      `She said "hi." Then left.`,
+			pos:              0,
+			expectedStartPos: 0,
+			expectedEndPos:   14,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			textTree, err := text.NewTreeFromString(tc.inputString)
+			require.NoError(t, err)
+			startPos, endPos := InnerSentenceObject(textTree, tc.pos, 1)
+			assert.Equal(t, tc.expectedStartPos, startPos)
+			assert.Equal(t, tc.expectedEndPos, endPos)
+		})
+	}
+}
+
+func TestASentenceObject(t *testing.T) {
+	textTree, err := text.NewTreeFromString("One. Two.")
+	require.NoError(t, err)
+	startPos, endPos := ASentenceObject(textTree, 0, 1)
+	assert.Equal(t, uint64(0), startPos)
+	assert.Equal(t, uint64(5), endPos)
+}
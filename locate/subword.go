@@ -0,0 +1,266 @@
+package locate
+
+import (
+	"io"
+	"unicode"
+
+	"github.com/aretext/aretext/text"
+)
+
+// subwordClass is a coarse per-rune classification used to find "subword"
+// boundaries inside an identifier: the lower-level pieces that `fooBar`,
+// `HTTPServer`, `utf8Decode`, and `snake_case_name` are each made of.
+type subwordClass int
+
+const (
+	// subwordSeparator is anything that isn't a letter or digit: whitespace,
+	// underscores, hyphens, and ordinary punctuation. Runs of separators are
+	// skipped the same way whitespace is skipped by the word motions.
+	subwordSeparator = subwordClass(iota)
+	subwordUpper
+	subwordLower
+	subwordDigit
+)
+
+// classifySubwordRune classifies r for subword splitting. Letters with no
+// case distinction (most CJK, for example) are treated like lowercase
+// letters: they join a run but never trigger a camelCase-style split.
+func classifySubwordRune(r rune) subwordClass {
+	switch {
+	case unicode.IsUpper(r):
+		return subwordUpper
+	case unicode.IsDigit(r):
+		return subwordDigit
+	case unicode.IsLetter(r):
+		return subwordLower
+	default:
+		return subwordSeparator
+	}
+}
+
+// subwordRun is a maximal run of runes belonging to one subword, or one run
+// of separator characters between subwords.
+type subwordRun struct {
+	start       uint64
+	end         uint64 // exclusive
+	isSeparator bool
+}
+
+// subwordRuns scans the whole document and splits it into subwordRuns.
+//
+// A boundary falls: between a separator and a non-separator on either side
+// (WordObject-style whitespace skipping); between a letter and a digit in
+// either direction (utf8Decode -> utf, 8, Decode); between a lowercase and
+// a following uppercase letter (fooBar -> foo, Bar); and before the last of
+// a run of uppercase letters when it's followed by a lowercase letter, so an
+// acronym releases its tail to the word it introduces (HTTPServer -> HTTP,
+// Server). That last rule needs one rune of lookback beyond the immediately
+// preceding class, so boundaries are computed into a slice first and then
+// grouped into runs, rather than decided as each rune streams in.
+func subwordRuns(tree *text.Tree) []subwordRun {
+	reader := tree.ReaderAtPosition(0)
+
+	var classes []subwordClass
+	for {
+		r, _, err := reader.ReadRune()
+		if err == io.EOF {
+			break
+		}
+		classes = append(classes, classifySubwordRune(r))
+	}
+
+	if len(classes) == 0 {
+		return nil
+	}
+
+	boundary := make([]bool, len(classes))
+	boundary[0] = true
+	for i := 1; i < len(classes); i++ {
+		prev, cur := classes[i-1], classes[i]
+		switch {
+		case prev == subwordSeparator && cur == subwordSeparator:
+			boundary[i] = false
+		case prev == subwordSeparator || cur == subwordSeparator:
+			boundary[i] = true
+		case prev == subwordDigit != (cur == subwordDigit):
+			boundary[i] = true
+		case prev == subwordLower && cur == subwordUpper:
+			boundary[i] = true
+		case prev == subwordUpper && cur == subwordUpper:
+			boundary[i] = false
+		case prev == subwordUpper && cur == subwordLower:
+			if i >= 2 && classes[i-2] == subwordUpper {
+				// The acronym's last letter actually starts the next word
+				// (HTTPServer: split before the "S" in "HTTPServer", not
+				// before the "e" that follows it).
+				boundary[i-1] = true
+			}
+			boundary[i] = false
+		default:
+			boundary[i] = false
+		}
+	}
+
+	var runs []subwordRun
+	for i, cls := range classes {
+		if boundary[i] || len(runs) == 0 {
+			runs = append(runs, subwordRun{start: uint64(i), end: uint64(i + 1), isSeparator: cls == subwordSeparator})
+		} else {
+			runs[len(runs)-1].end = uint64(i + 1)
+		}
+	}
+
+	return runs
+}
+
+// findSubwordRunContaining returns the index of the run spanning pos, or
+// the last run if pos is at or past the end of the document.
+func findSubwordRunContaining(runs []subwordRun, pos uint64) int {
+	for i, run := range runs {
+		if pos >= run.start && pos < run.end {
+			return i
+		}
+	}
+	return len(runs) - 1
+}
+
+// NextSubwordStart returns the position of the start of the next subword
+// (or the count'th next subword) after pos.
+func NextSubwordStart(tree *text.Tree, pos uint64, count uint64) uint64 {
+	if count == 0 {
+		count = 1
+	}
+
+	runs := subwordRuns(tree)
+
+	result := pos
+	remaining := count
+	for _, run := range runs {
+		if run.isSeparator || run.start <= pos {
+			continue
+		}
+
+		result = run.start
+		remaining--
+		if remaining == 0 {
+			break
+		}
+	}
+
+	return result
+}
+
+// NextSubwordEnd returns the position of the last character of the next
+// subword (or the count'th next subword) after pos.
+func NextSubwordEnd(tree *text.Tree, pos uint64, count uint64) uint64 {
+	if count == 0 {
+		count = 1
+	}
+
+	runs := subwordRuns(tree)
+
+	result := pos
+	remaining := count
+	for _, run := range runs {
+		if run.isSeparator || run.end == 0 {
+			continue
+		}
+
+		endPos := run.end - 1
+		if endPos <= result {
+			continue
+		}
+
+		result = endPos
+		remaining--
+		if remaining == 0 {
+			break
+		}
+	}
+
+	return result
+}
+
+// PrevSubwordStart returns the position of the start of the previous
+// subword (or the count'th previous subword) before pos.
+func PrevSubwordStart(tree *text.Tree, pos uint64, count uint64) uint64 {
+	if count == 0 {
+		count = 1
+	}
+
+	runs := subwordRuns(tree)
+
+	result := pos
+	remaining := count
+	for i := len(runs) - 1; i >= 0; i-- {
+		run := runs[i]
+		if run.isSeparator || run.start >= result {
+			continue
+		}
+
+		result = run.start
+		remaining--
+		if remaining == 0 {
+			break
+		}
+	}
+
+	return result
+}
+
+// subwordObjectImpl returns the start and end positions of the subword (or
+// count consecutive subwords) spanning pos, extended to include an adjacent
+// separator run when includeSurroundingSeparator is true. This mirrors
+// wordObjectImpl's treatment of whitespace around a word.
+func subwordObjectImpl(tree *text.Tree, pos uint64, count uint64, includeSurroundingSeparator bool) (uint64, uint64) {
+	if count == 0 {
+		count = 1
+	}
+
+	runs := subwordRuns(tree)
+	if len(runs) == 0 {
+		return pos, pos
+	}
+
+	startIdx := findSubwordRunContaining(runs, pos)
+	endIdx := startIdx
+	for i := uint64(1); i < count && endIdx+1 < len(runs); i++ {
+		endIdx++
+	}
+
+	startPos := runs[startIdx].start
+	endPos := runs[endIdx].end
+
+	if !includeSurroundingSeparator {
+		return startPos, endPos
+	}
+
+	if runs[startIdx].isSeparator {
+		if endIdx+1 < len(runs) {
+			endPos = runs[endIdx+1].end
+		}
+		return startPos, endPos
+	}
+
+	if endIdx+1 < len(runs) && runs[endIdx+1].isSeparator {
+		endPos = runs[endIdx+1].end
+	} else if startIdx > 0 && runs[startIdx-1].isSeparator {
+		startPos = runs[startIdx-1].start
+	}
+
+	return startPos, endPos
+}
+
+// SubwordObject returns the start and end positions of "a subword" (or
+// count consecutive subwords) under the cursor, including one adjacent
+// separator run, mirroring WordObject's "aw" semantics.
+func SubwordObject(tree *text.Tree, pos uint64, count uint64) (uint64, uint64) {
+	return subwordObjectImpl(tree, pos, count, true)
+}
+
+// InnerSubwordObject returns the start and end positions of the subword (or
+// count consecutive subwords) under the cursor, excluding any adjacent
+// separator, mirroring InnerWordObject's "iw" semantics.
+func InnerSubwordObject(tree *text.Tree, pos uint64, count uint64) (uint64, uint64) {
+	return subwordObjectImpl(tree, pos, count, false)
+}
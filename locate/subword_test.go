@@ -0,0 +1,110 @@
+package locate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aretext/aretext/text"
+)
+
+func TestNextSubwordStart(t *testing.T) {
+	testCases := []struct {
+		name        string
+		inputString string
+		pos         uint64
+		expectedPos uint64
+	}{
+		{
+			name:        "empty",
+			inputString: "",
+			pos:         0,
+			expectedPos: 0,
+		},
+		{
+			name:        "camelCase boundary",
+			inputString: "fooBar",
+			pos:         0,
+			expectedPos: 3,
+		},
+		{
+			name:        "acronym releases its tail to the next word",
+			inputString: "HTTPServer",
+			pos:         0,
+			expectedPos: 4,
+		},
+		{
+			name:        "letter to trailing digit run",
+			inputString: "abc123",
+			pos:         0,
+			expectedPos: 3,
+		},
+		{
+			name:        "digit run back to letters",
+			inputString: "utf8Decode",
+			pos:         3,
+			expectedPos: 4,
+		},
+		{
+			name:        "leading underscore is a separator",
+			inputString: "_foo",
+			pos:         0,
+			expectedPos: 1,
+		},
+		{
+			name:        "snake_case splits on underscores",
+			inputString: "snake_case_name",
+			pos:         0,
+			expectedPos: 6,
+		},
+		{
+			name:        "kebab-case splits on hyphens",
+			inputString: "kebab-case-name",
+			pos:         0,
+			expectedPos: 6,
+		},
+		{
+			name:        "utf-8 identifier with cased letters",
+			inputString: "naïveCamel",
+			pos:         0,
+			expectedPos: 5,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			textTree, err := text.NewTreeFromString(tc.inputString)
+			require.NoError(t, err)
+			actualPos := NextSubwordStart(textTree, tc.pos, 1)
+			assert.Equal(t, tc.expectedPos, actualPos)
+		})
+	}
+}
+
+func TestNextSubwordEnd(t *testing.T) {
+	textTree, err := text.NewTreeFromString("fooBar")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), NextSubwordEnd(textTree, 0, 1))
+	assert.Equal(t, uint64(5), NextSubwordEnd(textTree, 2, 1))
+}
+
+func TestPrevSubwordStart(t *testing.T) {
+	textTree, err := text.NewTreeFromString("fooBar")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), PrevSubwordStart(textTree, 5, 1))
+	assert.Equal(t, uint64(0), PrevSubwordStart(textTree, 3, 1))
+}
+
+func TestSubwordObject(t *testing.T) {
+	textTree, err := text.NewTreeFromString("snake_case_name")
+	require.NoError(t, err)
+
+	startPos, endPos := InnerSubwordObject(textTree, 0, 1)
+	assert.Equal(t, uint64(0), startPos)
+	assert.Equal(t, uint64(5), endPos)
+
+	startPos, endPos = SubwordObject(textTree, 0, 1)
+	assert.Equal(t, uint64(0), startPos)
+	assert.Equal(t, uint64(6), endPos)
+}
@@ -0,0 +1,164 @@
+package locate
+
+import (
+	"io"
+
+	"github.com/aretext/aretext/syntax/parser"
+	"github.com/aretext/aretext/text"
+)
+
+// tokenAtPosition returns the syntax token spanning pos, if any. syntaxParser
+// is nil when no syntax language is active for the buffer.
+func tokenAtPosition(syntaxParser *parser.Parser, pos uint64) (parser.Token, bool) {
+	if syntaxParser == nil {
+		return parser.Token{}, false
+	}
+
+	tokens := syntaxParser.TokensInRange(pos, pos+1)
+	if len(tokens) == 0 {
+		return parser.Token{}, false
+	}
+
+	return tokens[0], true
+}
+
+// NextTokenStart returns the position of the start of the next syntax
+// token (or the count'th next token) after pos. If syntaxParser is nil,
+// this falls back to NextWordStart.
+func NextTokenStart(tree *text.Tree, syntaxParser *parser.Parser, pos uint64, count uint64) uint64 {
+	if count == 0 {
+		count = 1
+	}
+
+	if syntaxParser == nil {
+		return NextWordStart(tree, pos, count, false, false, true)
+	}
+
+	tokens := syntaxParser.TokensInRange(pos+1, tree.NumChars())
+	result := pos
+	remaining := count
+	for _, tok := range tokens {
+		if tok.StartPos <= pos {
+			continue
+		}
+		result = tok.StartPos
+		remaining--
+		if remaining == 0 {
+			break
+		}
+	}
+
+	return result
+}
+
+// PrevTokenStart returns the position of the start of the previous syntax
+// token (or the count'th previous token) before pos. If syntaxParser is
+// nil, this falls back to PrevWordStart.
+func PrevTokenStart(tree *text.Tree, syntaxParser *parser.Parser, pos uint64, count uint64) uint64 {
+	if count == 0 {
+		count = 1
+	}
+
+	if syntaxParser == nil {
+		return PrevWordStart(tree, pos, count, false)
+	}
+
+	tokens := syntaxParser.TokensInRange(0, pos)
+	result := pos
+	remaining := count
+	for i := len(tokens) - 1; i >= 0; i-- {
+		tok := tokens[i]
+		if tok.StartPos >= result {
+			continue
+		}
+		result = tok.StartPos
+		remaining--
+		if remaining == 0 {
+			break
+		}
+	}
+
+	return result
+}
+
+// TokenObject returns the start and end positions of the full syntax token
+// under the cursor -- for example a whole qualified identifier that a
+// language's tokenizer treats as one token, `::` qualifiers included. If
+// syntaxParser is nil or pos isn't inside any token, this falls back to
+// WordObject.
+func TokenObject(tree *text.Tree, syntaxParser *parser.Parser, pos uint64) (uint64, uint64) {
+	tok, ok := tokenAtPosition(syntaxParser, pos)
+	if !ok {
+		return WordObject(tree, pos, 1)
+	}
+	return tok.StartPos, tok.EndPos
+}
+
+// InnerStringObject returns the start and end positions of the contents of
+// the string literal under the cursor, excluding its quote delimiters, read
+// directly off the syntax token rather than re-scanning the buffer for a
+// matching quote. If syntaxParser is nil or pos isn't inside a string
+// token, this falls back to InnerWordObject.
+func InnerStringObject(tree *text.Tree, syntaxParser *parser.Parser, pos uint64) (uint64, uint64) {
+	tok, ok := tokenAtPosition(syntaxParser, pos)
+	if !ok || tok.Role != parser.TokenRoleString || tok.EndPos-tok.StartPos < 2 {
+		return InnerWordObject(tree, pos, 1)
+	}
+	return tok.StartPos + 1, tok.EndPos - 1
+}
+
+// InnerCommentObject returns the start and end positions of the comment
+// body under the cursor, with a leading "//" or "#" marker (and the single
+// space after it, if any) stripped off. If syntaxParser is nil or pos isn't
+// inside a comment token, this falls back to InnerWordObject.
+func InnerCommentObject(tree *text.Tree, syntaxParser *parser.Parser, pos uint64) (uint64, uint64) {
+	tok, ok := tokenAtPosition(syntaxParser, pos)
+	if !ok || tok.Role != parser.TokenRoleComment {
+		return InnerWordObject(tree, pos, 1)
+	}
+
+	contentStart := tok.StartPos + commentMarkerLen(tree, tok.StartPos, tok.EndPos)
+	if contentStart > tok.EndPos {
+		contentStart = tok.EndPos
+	}
+
+	return contentStart, tok.EndPos
+}
+
+// commentMarkerLen returns the number of leading runes of a comment token
+// to skip to reach its body: the "//" or "#" marker itself, plus one space
+// after it if present.
+func commentMarkerLen(tree *text.Tree, startPos uint64, endPos uint64) uint64 {
+	reader := tree.ReaderAtPosition(startPos)
+
+	var markerLen uint64
+	r, _, err := reader.ReadRune()
+	if err != nil {
+		return 0
+	}
+
+	switch r {
+	case '#':
+		markerLen = 1
+	case '/':
+		r2, _, err := reader.ReadRune()
+		if err == nil && r2 == '/' {
+			markerLen = 2
+		} else {
+			return 0
+		}
+	default:
+		return 0
+	}
+
+	if startPos+markerLen >= endPos {
+		return markerLen
+	}
+
+	spaceReader := tree.ReaderAtPosition(startPos + markerLen)
+	if r, _, err := spaceReader.ReadRune(); err == io.EOF || err != nil || r != ' ' {
+		return markerLen
+	}
+
+	return markerLen + 1
+}
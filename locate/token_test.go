@@ -0,0 +1,139 @@
+package locate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aretext/aretext/text"
+)
+
+// These cover the syntaxParser == nil fallback paths documented on
+// NextTokenStart, PrevTokenStart, TokenObject, InnerStringObject, and
+// InnerCommentObject. Exercising the syntaxParser != nil paths would need a
+// working *parser.Parser built from a language's TokenizerRule set (Go,
+// Python, git-commit); syntax/parser has no concrete Parser implementation
+// in this snapshot yet, so those paths aren't testable here.
+
+func TestNextTokenStartNoLanguageFallsBackToWordStart(t *testing.T) {
+	testCases := []struct {
+		name        string
+		inputString string
+		pos         uint64
+		count       uint64
+		expectedPos uint64
+	}{
+		{
+			name:        "empty",
+			inputString: "",
+			pos:         0,
+			count:       1,
+			expectedPos: 0,
+		},
+		{
+			name:        "next word, same line",
+			inputString: "abc   defg   hij",
+			pos:         1,
+			count:       1,
+			expectedPos: 6,
+		},
+		{
+			name:        "count of two words",
+			inputString: "abc   defg   hij",
+			pos:         1,
+			count:       2,
+			expectedPos: 13,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			textTree, err := text.NewTreeFromString(tc.inputString)
+			require.NoError(t, err)
+
+			actualPos := NextTokenStart(textTree, nil, tc.pos, tc.count)
+			wantPos := NextWordStart(textTree, tc.pos, tc.count, false, false, true)
+			assert.Equal(t, tc.expectedPos, actualPos)
+			assert.Equal(t, wantPos, actualPos)
+		})
+	}
+}
+
+func TestPrevTokenStartNoLanguageFallsBackToWordStart(t *testing.T) {
+	testCases := []struct {
+		name        string
+		inputString string
+		pos         uint64
+		count       uint64
+	}{
+		{
+			name:        "empty",
+			inputString: "",
+			pos:         0,
+			count:       1,
+		},
+		{
+			name:        "prev word, same line",
+			inputString: "abc   defg   hij",
+			pos:         13,
+			count:       1,
+		},
+		{
+			name:        "count of two words",
+			inputString: "abc   defg   hij",
+			pos:         13,
+			count:       2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			textTree, err := text.NewTreeFromString(tc.inputString)
+			require.NoError(t, err)
+
+			actualPos := PrevTokenStart(textTree, nil, tc.pos, tc.count)
+			wantPos := PrevWordStart(textTree, tc.pos, tc.count, false)
+			assert.Equal(t, wantPos, actualPos)
+		})
+	}
+}
+
+func TestTokenObjectNoLanguageFallsBackToWordObject(t *testing.T) {
+	inputString := "abc   defg   hij"
+	textTree, err := text.NewTreeFromString(inputString)
+	require.NoError(t, err)
+
+	for pos := uint64(0); pos < uint64(len(inputString)); pos++ {
+		startPos, endPos := TokenObject(textTree, nil, pos)
+		wantStartPos, wantEndPos := WordObject(textTree, pos, 1)
+		assert.Equal(t, wantStartPos, startPos)
+		assert.Equal(t, wantEndPos, endPos)
+	}
+}
+
+func TestInnerStringObjectNoLanguageFallsBackToWordObject(t *testing.T) {
+	inputString := `abc "defg" hij`
+	textTree, err := text.NewTreeFromString(inputString)
+	require.NoError(t, err)
+
+	for pos := uint64(0); pos < uint64(len(inputString)); pos++ {
+		startPos, endPos := InnerStringObject(textTree, nil, pos)
+		wantStartPos, wantEndPos := InnerWordObject(textTree, pos, 1)
+		assert.Equal(t, wantStartPos, startPos)
+		assert.Equal(t, wantEndPos, endPos)
+	}
+}
+
+func TestInnerCommentObjectNoLanguageFallsBackToWordObject(t *testing.T) {
+	inputString := "abc // defg hij"
+	textTree, err := text.NewTreeFromString(inputString)
+	require.NoError(t, err)
+
+	for pos := uint64(0); pos < uint64(len(inputString)); pos++ {
+		startPos, endPos := InnerCommentObject(textTree, nil, pos)
+		wantStartPos, wantEndPos := InnerWordObject(textTree, pos, 1)
+		assert.Equal(t, wantStartPos, startPos)
+		assert.Equal(t, wantEndPos, endPos)
+	}
+}
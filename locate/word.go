@@ -0,0 +1,473 @@
+package locate
+
+import (
+	"io"
+	"unicode"
+
+	"github.com/aretext/aretext/text"
+	"github.com/aretext/aretext/text/segment"
+)
+
+// wbClass approximates the Word_Break property classes from UAX #29 table
+// 3a. This replaces the old ASCII-range isPunct classifier, which treated
+// every non-ASCII letter (Cyrillic, CJK, combining marks, ...) as a single
+// run of "non-punctuation", merging whole sentences of Japanese or Russian
+// text into one "word".
+//
+// Simplifications versus the full UAX #29 table, each mirroring a
+// precedent already in this codebase (see LineBreaker's own tailoring and
+// its LB30b note): Hebrew_Letter is folded into ALetter, so the
+// Hebrew-specific WB7a/7b/7c rules don't apply; WB15/16 regional indicator
+// pairing uses an odd/even counter rather than resetting exactly at
+// intervening breaks (the same approximation LineBreaker uses for LB30a);
+// and Extended_Pictographic runes aren't distinguished from other symbols:
+// they're classed as Other, and a run of Other runes (with any
+// Extend/Format/ZWJ between them) stays together, which keeps a ZWJ emoji
+// sequence (WB3c) in one word.
+type wbClass int
+
+const (
+	wbOther wbClass = iota
+	wbNewline
+	wbWSegSpace
+	wbExtendFormatZWJ
+	wbALetter
+	wbNumeric
+	wbKatakana
+	wbExtendNumLet
+	wbMidLetter
+	wbMidNum
+	wbMidNumLet
+	wbRegionalIndicator
+)
+
+// isBlankClass reports whether cls represents whitespace or a line break,
+// rather than visible content (punctuation counts as content).
+func isBlankClass(cls wbClass) bool {
+	return cls == wbNewline || cls == wbWSegSpace
+}
+
+// isWordJoining reports whether cls is one of the classes UAX #29 treats as
+// "letter-like" for the purposes of WB5/WB9/WB10/WB13 (ALetter, Numeric,
+// and Katakana all join with themselves and each other).
+func isWordJoining(cls wbClass) bool {
+	return cls == wbALetter || cls == wbNumeric || cls == wbKatakana
+}
+
+// wbClassForRune classifies r into a wbClass, using Go's unicode category
+// tables as a stand-in for the official WordBreakProperty.txt data (the
+// same kind of stand-in LineBreaker's gen_props.go-generated tables avoid
+// needing, but which isn't worth a generated-table pipeline for a single
+// motion family).
+func wbClassForRune(r rune) wbClass {
+	switch r {
+	case '\n', '\v', '\f', '', ' ', ' ', '\r':
+		return wbNewline
+	case '‍':
+		return wbExtendFormatZWJ
+	case '_':
+		return wbExtendNumLet
+	case '\'', '’':
+		return wbMidNumLet
+	case '.', ',':
+		return wbMidNum
+	case ':', '·':
+		return wbMidLetter
+	}
+
+	switch {
+	case unicode.IsSpace(r):
+		return wbWSegSpace
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return wbExtendFormatZWJ
+	case unicode.In(r, unicode.Katakana):
+		return wbKatakana
+	case r >= 0x1F1E6 && r <= 0x1F1FF:
+		return wbRegionalIndicator
+	case unicode.IsDigit(r):
+		return wbNumeric
+	case unicode.IsLetter(r):
+		return wbALetter
+	default:
+		return wbOther
+	}
+}
+
+// wbBreaker drives a single forward pass over a stream of wbClass values
+// and decides, as each new class arrives, whether a word boundary falls
+// between it and the previous one. This mirrors LineBreaker's own
+// single-pass, two-classes-of-lookback design (lastProp/lastLastProp)
+// rather than the full backtracking lookahead UAX #29 describes, which
+// the same online reformulation LineBreaker already uses for UAX #14
+// makes unnecessary here too.
+type wbBreaker struct {
+	class     wbClass
+	prevClass wbClass
+	hasClass  bool
+	riOdd     bool
+}
+
+// boundaryBefore reports whether a word boundary falls between the
+// previously processed class and cls, then records cls as processed.
+func (wb *wbBreaker) boundaryBefore(cls wbClass) bool {
+	brk := wb.hasClass && wb.decide(cls)
+
+	if cls != wbExtendFormatZWJ {
+		wb.prevClass = wb.class
+		wb.class = cls
+		wb.riOdd = cls == wbRegionalIndicator && !wb.riOdd
+	}
+	wb.hasClass = true
+
+	return brk
+}
+
+func (wb *wbBreaker) decide(cls wbClass) bool {
+	last := wb.class
+
+	// WB4: Extend/Format/ZWJ never break; they stay invisible to every
+	// other rule, joined to whatever came before them.
+	if cls == wbExtendFormatZWJ {
+		return false
+	}
+
+	// WB3a/WB3b: always break before or after a newline.
+	if last == wbNewline || cls == wbNewline {
+		return true
+	}
+
+	switch {
+	case last == wbWSegSpace && cls == wbWSegSpace:
+		// WB3d: keep runs of horizontal whitespace together.
+		return false
+	case isWordJoining(last) && isWordJoining(cls):
+		// WB5/WB8/WB9/WB10: letters and digits join themselves and each other.
+		return false
+	case last == wbKatakana && cls == wbKatakana:
+		// WB13: keep Katakana runs together (redundant with the case above,
+		// kept for clarity since Katakana is also word-joining).
+		return false
+	case last == wbExtendNumLet && (isWordJoining(cls) || cls == wbExtendNumLet):
+		// WB13a: ExtendNumLet (e.g. "_") glues to what follows.
+		return false
+	case isWordJoining(last) && cls == wbExtendNumLet:
+		// WB13b: ExtendNumLet glues to what came before.
+		return false
+	case cls == wbMidLetter && isWordJoining(last):
+		// WB6: hold the decision; resolved when the following class arrives.
+		return false
+	case cls == wbMidNumLet && (isWordJoining(last) || last == wbNumeric):
+		// WB6/WB11 via MidNumLet, which serves both letter and numeric runs.
+		return false
+	case cls == wbMidNum && last == wbNumeric:
+		// WB11: hold the decision for a numeric MidNum, as above.
+		return false
+	case (last == wbMidLetter || last == wbMidNumLet) && isWordJoining(cls) && isWordJoining(wb.prevClass):
+		// WB7: ALetter (MidLetter|MidNumLet) ALetter stays together.
+		return false
+	case (last == wbMidNum || last == wbMidNumLet) && cls == wbNumeric && wb.prevClass == wbNumeric:
+		// WB12: Numeric (MidNum|MidNumLet) Numeric stays together.
+		return false
+	case last == wbRegionalIndicator && cls == wbRegionalIndicator && !wb.riOdd:
+		// WB15/WB16: pair up regional indicators.
+		return false
+	case last == wbOther && cls == wbOther:
+		// Keep runs of punctuation and symbols together, as vim's "w" does.
+		return false
+	}
+
+	// WB999: break everywhere else.
+	return true
+}
+
+// wordRun is a maximal run of runes sharing one wbClass, as found by
+// wordRuns.
+type wordRun struct {
+	start uint64
+	end   uint64 // exclusive
+	class wbClass
+}
+
+// wordRuns scans the whole document and splits it into wordRuns using the
+// UAX #29 word boundary rules (approximated by wbBreaker). If
+// includePunctuation is true, punctuation counts as part of a word: every
+// non-blank class is collapsed into a single "big word" class before
+// classification, matching vim's `W`/`E`/`B` motions, which only stop at
+// whitespace.
+func wordRuns(tree *text.Tree, includePunctuation bool) []wordRun {
+	reader := tree.ReaderAtPosition(0)
+
+	var runs []wordRun
+	var wb wbBreaker
+	var pos uint64
+	for {
+		r, _, err := reader.ReadRune()
+		if err == io.EOF {
+			break
+		}
+
+		cls := wbClassForRune(r)
+		if includePunctuation && cls != wbNewline && cls != wbWSegSpace && cls != wbExtendFormatZWJ {
+			cls = wbALetter
+		}
+
+		if wb.boundaryBefore(cls) || len(runs) == 0 {
+			runs = append(runs, wordRun{start: pos, end: pos + 1, class: cls})
+		} else {
+			runs[len(runs)-1].end = pos + 1
+		}
+
+		pos++
+	}
+
+	return runs
+}
+
+// findRunContaining returns the index of the run spanning pos, or the last
+// run if pos is at or past the end of the document.
+func findRunContaining(runs []wordRun, pos uint64) int {
+	for i, run := range runs {
+		if pos >= run.start && pos < run.end {
+			return i
+		}
+	}
+	return len(runs) - 1
+}
+
+// lineEndPosition returns the position of the last character on lineNum
+// (before its newline), or the end of the document for the last line.
+func lineEndPosition(tree *text.Tree, lineNum uint64) uint64 {
+	if lineNum+1 < tree.NumLines() {
+		end := tree.LineStartPosition(lineNum + 1)
+		if end > 0 {
+			return end - 1
+		}
+		return end
+	}
+	return tree.NumChars()
+}
+
+// NextWordStart returns the position of the start of the next word (or the
+// count'th next word) after pos, using Unicode word boundaries (see
+// wbClass) instead of ASCII-only classification.
+//
+// If stopAtEndOfLine is true, the search won't reach past the end of pos's
+// current line; this is for operator-pending motions like "dw", which
+// (unlike the bare "w" motion) shouldn't delete across a line boundary.
+//
+// If allowEmptyLine is true, the start of a blank line counts as a word
+// start in its own right, matching how "w" stops on blank lines; if false,
+// blank lines are skipped over like ordinary whitespace.
+func NextWordStart(tree *text.Tree, pos uint64, count uint64, includePunctuation bool, stopAtEndOfLine bool, allowEmptyLine bool) uint64 {
+	if count == 0 {
+		count = 1
+	}
+
+	runs := wordRuns(tree, includePunctuation)
+
+	var endOfLine uint64
+	if stopAtEndOfLine {
+		endOfLine = lineEndPosition(tree, tree.LineNumForPosition(pos))
+	}
+
+	result := pos
+	remaining := count
+	for _, run := range runs {
+		if run.start <= pos {
+			continue
+		}
+
+		if isBlankClass(run.class) {
+			if !allowEmptyLine || !lineIsBlank(tree, tree.LineStartPosition(tree.LineNumForPosition(run.start))) {
+				continue
+			}
+		}
+
+		if stopAtEndOfLine && run.start > endOfLine {
+			return endOfLine
+		}
+
+		result = run.start
+		remaining--
+		if remaining == 0 {
+			break
+		}
+	}
+
+	if remaining > 0 {
+		// Fewer than count words follow pos, so go to the end of the
+		// document (or of the line, if stopAtEndOfLine).
+		result = tree.NumChars()
+		if stopAtEndOfLine && result > endOfLine {
+			result = endOfLine
+		}
+	}
+
+	return result
+}
+
+// NextWordEnd returns the position of the last character of the next word
+// (or the count'th next word) after pos.
+func NextWordEnd(tree *text.Tree, pos uint64, count uint64, includePunctuation bool) uint64 {
+	if count == 0 {
+		count = 1
+	}
+
+	runs := wordRuns(tree, includePunctuation)
+
+	result := pos
+	remaining := count
+	for _, run := range runs {
+		if isBlankClass(run.class) || run.end == 0 {
+			continue
+		}
+
+		endPos := run.end - 1
+		if endPos <= result {
+			continue
+		}
+
+		result = endPos
+		remaining--
+		if remaining == 0 {
+			break
+		}
+	}
+
+	return result
+}
+
+// PrevWordStart returns the position of the start of the previous word (or
+// the count'th previous word) before pos.
+func PrevWordStart(tree *text.Tree, pos uint64, count uint64, includePunctuation bool) uint64 {
+	if count == 0 {
+		count = 1
+	}
+
+	runs := wordRuns(tree, includePunctuation)
+
+	result := pos
+	remaining := count
+	for i := len(runs) - 1; i >= 0; i-- {
+		run := runs[i]
+		if run.start >= result {
+			continue
+		}
+
+		// A blank line counts as a word start, as in NextWordStart.
+		if isBlankClass(run.class) {
+			lineStart := tree.LineStartPosition(tree.LineNumForPosition(run.start))
+			if run.start != lineStart || !lineIsBlank(tree, lineStart) {
+				continue
+			}
+		}
+
+		result = run.start
+		remaining--
+		if remaining == 0 {
+			break
+		}
+	}
+
+	return result
+}
+
+// wordObjectImpl returns the start and end positions of the word (or count
+// consecutive words) spanning pos. When includeSurroundingWhitespace is
+// true, the range is extended by whichever adjacent run of whitespace
+// "belongs" to the word: trailing whitespace if there is any, otherwise
+// leading whitespace; if the word itself starts on whitespace, the range
+// extends forward to include the word that whitespace leads into.
+func wordObjectImpl(tree *text.Tree, pos uint64, count uint64, includeSurroundingWhitespace bool) (uint64, uint64) {
+	if count == 0 {
+		count = 1
+	}
+
+	runs := wordRuns(tree, false)
+	if len(runs) == 0 {
+		return pos, pos
+	}
+
+	startIdx := findRunContaining(runs, pos)
+	endIdx := startIdx
+	for i := uint64(1); i < count && endIdx+1 < len(runs); i++ {
+		endIdx++
+	}
+
+	startPos := runs[startIdx].start
+	endPos := runs[endIdx].end
+
+	if !includeSurroundingWhitespace {
+		return startPos, endPos
+	}
+
+	if isBlankClass(runs[startIdx].class) {
+		j := endIdx
+		for j+1 < len(runs) && isBlankClass(runs[j+1].class) {
+			j++
+		}
+		if j+1 < len(runs) {
+			endPos = runs[j+1].end
+		} else {
+			endPos = runs[j].end
+		}
+		return startPos, endPos
+	}
+
+	if endIdx+1 < len(runs) && isBlankClass(runs[endIdx+1].class) {
+		j := endIdx + 1
+		for j+1 < len(runs) && isBlankClass(runs[j+1].class) {
+			j++
+		}
+		endPos = runs[j].end
+	} else if startIdx > 0 && isBlankClass(runs[startIdx-1].class) {
+		j := startIdx - 1
+		for j > 0 && isBlankClass(runs[j-1].class) {
+			j--
+		}
+		startPos = runs[j].start
+	}
+
+	return startPos, endPos
+}
+
+// WordObject returns the start and end positions of "a word" (or count
+// consecutive words) under the cursor, including adjacent whitespace per
+// vim's "aw" semantics; see wordObjectImpl.
+func WordObject(tree *text.Tree, pos uint64, count uint64) (uint64, uint64) {
+	return wordObjectImpl(tree, pos, count, true)
+}
+
+// InnerWordObject returns the start and end positions of the word (or count
+// consecutive words) under the cursor, excluding adjacent whitespace. If
+// the cursor is on a blank line, the inner word is the empty range at the
+// cursor, mirroring InnerParagraphObject's treatment of blank lines.
+func InnerWordObject(tree *text.Tree, pos uint64, count uint64) (uint64, uint64) {
+	lineNum := tree.LineNumForPosition(pos)
+	if lineIsBlank(tree, tree.LineStartPosition(lineNum)) {
+		return pos, pos
+	}
+	return wordObjectImpl(tree, pos, count, false)
+}
+
+// isPunct reports whether seg's leading rune is "punctuation" for the
+// purposes of word motions: visible, but neither a letter, digit, nor part
+// of the ExtendNumLet/Extend/Format/ZWJ classes that glue onto a word.
+func isPunct(seg *segment.Segment) bool {
+	runes := seg.Runes()
+	if len(runes) == 0 {
+		return false
+	}
+
+	r := runes[0]
+	if unicode.IsControl(r) || unicode.IsSpace(r) {
+		return false
+	}
+
+	switch wbClassForRune(r) {
+	case wbALetter, wbNumeric, wbKatakana, wbExtendNumLet, wbExtendFormatZWJ, wbRegionalIndicator:
+		return false
+	default:
+		return true
+	}
+}
@@ -96,7 +96,7 @@ func TestNextWordStart(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			textTree, err := text.NewTreeFromString(tc.inputString)
 			require.NoError(t, err)
-			actualPos := NextWordStart(textTree, tc.pos)
+			actualPos := NextWordStart(textTree, tc.pos, 1, false, false, true)
 			assert.Equal(t, tc.expectedPos, actualPos)
 		})
 	}
@@ -181,7 +181,7 @@ func TestNextWordEnd(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			textTree, err := text.NewTreeFromString(tc.inputString)
 			require.NoError(t, err)
-			actualPos := NextWordEnd(textTree, tc.pos)
+			actualPos := NextWordEnd(textTree, tc.pos, 1, false)
 			assert.Equal(t, tc.expectedPos, actualPos)
 		})
 	}
@@ -248,7 +248,7 @@ func TestPrevWordStart(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			textTree, err := text.NewTreeFromString(tc.inputString)
 			require.NoError(t, err)
-			actualPos := PrevWordStart(textTree, tc.pos)
+			actualPos := PrevWordStart(textTree, tc.pos, 1, false)
 			assert.Equal(t, tc.expectedPos, actualPos)
 		})
 	}
@@ -425,7 +425,7 @@ func TestWordObject(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			textTree, err := text.NewTreeFromString(tc.inputString)
 			require.NoError(t, err)
-			startPos, endPos := WordObject(textTree, tc.pos)
+			startPos, endPos := WordObject(textTree, tc.pos, 1)
 			assert.Equal(t, tc.expectedStartPos, startPos)
 			assert.Equal(t, tc.expectedEndPos, endPos)
 		})
@@ -603,7 +603,7 @@ func TestInnerWordObject(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			textTree, err := text.NewTreeFromString(tc.inputString)
 			require.NoError(t, err)
-			startPos, endPos := InnerWordObject(textTree, tc.pos)
+			startPos, endPos := InnerWordObject(textTree, tc.pos, 1)
 			assert.Equal(t, tc.expectedStartPos, startPos)
 			assert.Equal(t, tc.expectedEndPos, endPos)
 		})
@@ -0,0 +1,62 @@
+package locate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aretext/aretext/text"
+)
+
+// TestNextWordStartUnicode checks that word motions follow Unicode word
+// boundaries (UAX #29) rather than treating a whole run of non-ASCII text as
+// a single word.
+func TestNextWordStartUnicode(t *testing.T) {
+	testCases := []struct {
+		name        string
+		inputString string
+		pos         uint64
+		expectedPos uint64
+	}{
+		{
+			name:        "cyrillic words separated by space",
+			inputString: "Добрый день",
+			pos:         0,
+			expectedPos: 7,
+		},
+		{
+			name:        "japanese hiragana run has no internal word boundary",
+			inputString: "あいうえお かきくけこ",
+			pos:         0,
+			expectedPos: 6,
+		},
+		{
+			name:        "emoji ZWJ sequence stays one word",
+			inputString: "👩‍👩‍👧‍👦 next",
+			pos:         0,
+			expectedPos: 8,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			textTree, err := text.NewTreeFromString(tc.inputString)
+			require.NoError(t, err)
+			actualPos := NextWordStart(textTree, tc.pos, 1, false, false, true)
+			assert.Equal(t, tc.expectedPos, actualPos)
+		})
+	}
+}
+
+// TestWordObjectUnicode checks that "aw"/"iw"-style word objects treat a
+// Cyrillic or Japanese word as a single unit rather than splitting on every
+// rune.
+func TestWordObjectUnicode(t *testing.T) {
+	textTree, err := text.NewTreeFromString("Добрый день")
+	require.NoError(t, err)
+
+	startPos, endPos := InnerWordObject(textTree, 0, 1)
+	assert.Equal(t, uint64(0), startPos)
+	assert.Equal(t, uint64(6), endPos)
+}
@@ -0,0 +1,169 @@
+// Package plugin loads user-supplied Lua scripts and exposes editor
+// primitives to them, so users can add new Actions without patching aretext.
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	lua "github.com/yuin/gopher-lua"
+
+	"github.com/aretext/aretext/input"
+	"github.com/aretext/aretext/locate"
+	"github.com/aretext/aretext/state"
+)
+
+// pluginGlob matches the Lua scripts that are loaded on startup.
+const pluginGlob = "*.lua"
+
+// LoadDir loads every `*.lua` script in dir (typically
+// `~/.config/aretext/plugins`), running each to completion so it can call
+// `aretext.register_action` to add named Actions to the input registry.
+// A script that fails to load or run is skipped with its error returned
+// alongside the others; a single broken plugin shouldn't prevent the rest
+// from loading.
+func LoadDir(dir string) []error {
+	paths, err := filepath.Glob(filepath.Join(dir, pluginGlob))
+	if err != nil {
+		return []error{err}
+	}
+
+	var errs []error
+	for _, path := range paths {
+		if err := loadScript(path); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+		}
+	}
+
+	return errs
+}
+
+func loadScript(path string) error {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	L := lua.NewState()
+	registerAPI(L)
+
+	if err := L.DoString(string(src)); err != nil {
+		L.Close()
+		return err
+	}
+
+	// Intentionally leak L: registered action closures capture it and must
+	// keep running for the lifetime of the editor process.
+	return nil
+}
+
+// registerAPI installs the `aretext` table into L, exposing the editor
+// mutators and locators that a script can call.
+func registerAPI(L *lua.LState) {
+	mod := L.NewTable()
+	L.SetGlobal("aretext", mod)
+
+	L.SetField(mod, "register_action", L.NewFunction(luaRegisterAction(L)))
+	L.SetField(mod, "insert_rune", L.NewFunction(luaInsertRune))
+	L.SetField(mod, "delete_range", L.NewFunction(luaDeleteRange))
+	L.SetField(mod, "cursor_pos", L.NewFunction(luaCursorPos))
+	L.SetField(mod, "move_cursor", L.NewFunction(luaMoveCursor))
+	L.SetField(mod, "word_object", L.NewFunction(luaWordObject))
+}
+
+// scriptState stashes the *state.EditorState for the duration of a single
+// Action invocation, since Lua function signatures can't carry it directly.
+var scriptState *state.EditorState
+
+func withScriptState(s *state.EditorState, fn func()) {
+	prev := scriptState
+	scriptState = s
+	defer func() { scriptState = prev }()
+	fn()
+}
+
+// luaRegisterAction implements `aretext.register_action(name, fn)`, wrapping
+// the Lua function `fn` as a named input.Action.
+func luaRegisterAction(L *lua.LState) lua.LGFunction {
+	return func(L *lua.LState) int {
+		name := L.CheckString(1)
+		fn := L.CheckFunction(2)
+
+		input.RegisterAction(name, func(args input.ActionArgs) input.Action {
+			return func(s *state.EditorState) {
+				withScriptState(s, func() {
+					if err := L.CallByParam(lua.P{
+						Fn:      fn,
+						NRet:    0,
+						Protect: true,
+					}); err != nil {
+						state.SetStatusMsg(s, state.StatusMsg{
+							Style: state.StatusMsgStyleError,
+							Text:  fmt.Sprintf("plugin action %q failed: %s", name, err),
+						})
+					}
+				})
+			}
+		})
+
+		return 0
+	}
+}
+
+func luaInsertRune(L *lua.LState) int {
+	r := L.CheckString(1)
+	if scriptState != nil && len(r) > 0 {
+		state.InsertRune(scriptState, []rune(r)[0])
+	}
+	return 0
+}
+
+func luaDeleteRange(L *lua.LState) int {
+	start := uint64(L.CheckInt64(1))
+	end := uint64(L.CheckInt64(2))
+	if scriptState != nil {
+		state.DeleteRange(scriptState, func(params state.LocatorParams) (uint64, uint64) {
+			return start, end
+		}, state.DefaultClipboardPage)
+	}
+	return 0
+}
+
+func luaCursorPos(L *lua.LState) int {
+	if scriptState == nil {
+		L.Push(lua.LNumber(0))
+		return 1
+	}
+	L.Push(lua.LNumber(scriptState.DocumentBuffer().CursorPosition()))
+	return 1
+}
+
+func luaMoveCursor(L *lua.LState) int {
+	pos := uint64(L.CheckInt64(1))
+	if scriptState != nil {
+		state.MoveCursor(scriptState, func(params state.LocatorParams) uint64 {
+			return pos
+		})
+	}
+	return 0
+}
+
+// luaWordObject exposes locate.WordObject so a script can compose with
+// aretext's existing text-object primitives instead of re-implementing word
+// boundary detection.
+func luaWordObject(L *lua.LState) int {
+	count := uint64(L.OptInt(1, 1))
+	if scriptState == nil {
+		L.Push(lua.LNumber(0))
+		L.Push(lua.LNumber(0))
+		return 2
+	}
+
+	tree := scriptState.DocumentBuffer().TextTree()
+	pos := scriptState.DocumentBuffer().CursorPosition()
+	startPos, endPos := locate.WordObject(tree, pos, count)
+	L.Push(lua.LNumber(startPos))
+	L.Push(lua.LNumber(endPos))
+	return 2
+}
@@ -0,0 +1,102 @@
+package state
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/aretext/aretext/text/segment"
+)
+
+// caseConv is a per-grapheme-cluster case conversion, used so multi-rune
+// clusters (for example "ß" -> "SS") convert correctly instead of rune by rune.
+type caseConv func(cluster string) string
+
+func upperCluster(cluster string) string {
+	// strings.ToUpper leaves "ß" as is, since it has no single-rune
+	// uppercase form, so expand it to "SS" first.
+	return strings.ToUpper(strings.ReplaceAll(cluster, "ß", "SS"))
+}
+
+func lowerCluster(cluster string) string {
+	return strings.ToLower(cluster)
+}
+
+// titleClusterState tracks whether the next letter cluster starts a new word,
+// so TitlecaseSelection can decide whether to upper- or lower-case it.
+type titleClusterState struct {
+	atWordStart bool
+}
+
+func (st *titleClusterState) convert(cluster string) string {
+	runes := []rune(cluster)
+	isLetter := len(runes) > 0 && unicode.IsLetter(runes[0])
+
+	var result string
+	if isLetter && st.atWordStart {
+		result = strings.ToUpper(cluster)
+	} else {
+		result = strings.ToLower(cluster)
+	}
+
+	st.atWordStart = !isLetter
+	return result
+}
+
+// convertRangeByGraphemeCluster replaces the text in [startPos, endPos) with
+// the result of applying conv to each grapheme cluster in the range, then
+// commits a single undo entry for the whole replacement.
+func convertRangeByGraphemeCluster(s *EditorState, startPos, endPos uint64, conv caseConv) {
+	if startPos >= endPos {
+		return
+	}
+
+	BeginUndoEntry(s)
+	defer CommitUndoEntry(s)
+
+	reader := s.documentBuffer.textTree.ReaderAtPosition(startPos)
+	gcIter := segment.NewGraphemeClusterIter(reader)
+	seg := segment.Empty()
+
+	var b strings.Builder
+	for pos := startPos; pos < endPos; {
+		if err := gcIter.NextSegment(seg); err != nil {
+			break
+		}
+		b.WriteString(conv(string(seg.Runes())))
+		pos += uint64(len(seg.Runes()))
+	}
+
+	DeleteRange(s, func(params LocatorParams) (uint64, uint64) {
+		return startPos, endPos
+	}, clipboardPageNone)
+	MoveCursor(s, func(params LocatorParams) uint64 {
+		return startPos
+	})
+	InsertText(s, b.String())
+}
+
+// UppercaseSelection replaces the selected range, computed by selectionEndLoc
+// from the current cursor, with its uppercased form.
+func UppercaseSelection(s *EditorState, selectionEndLoc Locator) {
+	startPos := s.documentBuffer.cursor.position
+	endPos := selectionEndLoc(s.locatorParams())
+	convertRangeByGraphemeCluster(s, startPos, endPos, upperCluster)
+}
+
+// LowercaseSelection replaces the selected range, computed by selectionEndLoc
+// from the current cursor, with its lowercased form.
+func LowercaseSelection(s *EditorState, selectionEndLoc Locator) {
+	startPos := s.documentBuffer.cursor.position
+	endPos := selectionEndLoc(s.locatorParams())
+	convertRangeByGraphemeCluster(s, startPos, endPos, lowerCluster)
+}
+
+// TitlecaseSelection replaces the selected range, computed by selectionEndLoc
+// from the current cursor, with its titlecased form (the first letter of
+// each word uppercased, every other rune lowercased).
+func TitlecaseSelection(s *EditorState, selectionEndLoc Locator) {
+	startPos := s.documentBuffer.cursor.position
+	endPos := selectionEndLoc(s.locatorParams())
+	st := &titleClusterState{atWordStart: true}
+	convertRangeByGraphemeCluster(s, startPos, endPos, st.convert)
+}
@@ -0,0 +1,31 @@
+package state
+
+import "github.com/aretext/aretext/clipboard"
+
+// PastePostprocessor transforms pasted text before it's inserted into the
+// document, based on the sniffed MIME type of the payload.
+type PastePostprocessor func(payload clipboard.Payload) string
+
+// pastePostprocessors holds the postprocessors registered for each MIME type.
+var pastePostprocessors = make(map[string]PastePostprocessor)
+
+// RegisterPastePostprocessor associates a postprocessor with a MIME type
+// (as sniffed by clipboard.SniffMIMEType), so config can customize how a
+// paste is handled based on its content: reflowing long lines for
+// "text/plain", stripping diff markers for "text/x-patch", or re-indenting
+// source pasted into an existing function.
+func RegisterPastePostprocessor(mimeType string, postprocessor PastePostprocessor) {
+	pastePostprocessors[mimeType] = postprocessor
+}
+
+// ApplyPastePostprocessor runs the postprocessor registered for the
+// payload's MIME type, if any, and returns the (possibly transformed) text
+// to insert. If no postprocessor is registered for the MIME type, the
+// payload's text is returned unmodified.
+func ApplyPastePostprocessor(payload clipboard.Payload) string {
+	postprocessor, ok := pastePostprocessors[payload.MIMEType]
+	if !ok {
+		return payload.Text
+	}
+	return postprocessor(payload)
+}
@@ -0,0 +1,134 @@
+package state
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// RestoreLastPosition controls whether closing and reopening a file restores
+// the cursor position and view offset it had when it was last closed.
+// This is opt-in so a fresh `aretext somefile` doesn't surprise a user by
+// jumping to the middle of the file.
+type RestoreLastPosition bool
+
+// sessionPositionState is the cursor/view state persisted for a single file.
+type sessionPositionState struct {
+	// CursorPos is the UTF-8 character offset of the cursor.
+	CursorPos uint64 `json:"cursorPos"`
+
+	// ViewOrigin is the UTF-8 character offset of the first line visible in the view.
+	ViewOrigin uint64 `json:"viewOrigin"`
+
+	// ModTimeUnixNano is the modification time of the file when the position was saved,
+	// used to detect that the file changed on disk since the position was recorded.
+	ModTimeUnixNano int64 `json:"modTimeUnixNano"`
+}
+
+// sessionStoreDir returns the directory where per-file session state is stored,
+// creating it if necessary.
+func sessionStoreDir() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(cacheDir, "aretext", "session")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	return dir, nil
+}
+
+// sessionKeyForPath returns a filesystem-safe key derived from an absolute file path.
+func sessionKeyForPath(absPath string) string {
+	sum := sha256.Sum256([]byte(absPath))
+	return hex.EncodeToString(sum[:])
+}
+
+// SavePositionForFile persists the cursor position and view origin for absPath,
+// so they can be restored the next time the file is opened.
+// Callers should pass the file's modification time at the moment of saving;
+// LoadPositionForFile won't return a saved position for a file that has since
+// been modified by another process.
+func SavePositionForFile(absPath string, modTime time.Time, cursorPos, viewOrigin uint64) error {
+	dir, err := sessionStoreDir()
+	if err != nil {
+		return err
+	}
+
+	saved := sessionPositionState{
+		CursorPos:       cursorPos,
+		ViewOrigin:      viewOrigin,
+		ModTimeUnixNano: modTime.UnixNano(),
+	}
+
+	data, err := json.Marshal(saved)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, sessionKeyForPath(absPath))
+	return os.WriteFile(path, data, 0o644)
+}
+
+// LoadPositionForFile returns the previously saved cursor position and view origin
+// for absPath, if one was saved while the file had the given modification time.
+// The second return value is false if there's no saved position, or if the file's
+// modification time no longer matches what was saved (the file changed since then).
+func LoadPositionForFile(absPath string, modTime time.Time) (cursorPos, viewOrigin uint64, ok bool) {
+	dir, err := sessionStoreDir()
+	if err != nil {
+		return 0, 0, false
+	}
+
+	path := filepath.Join(dir, sessionKeyForPath(absPath))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	var saved sessionPositionState
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return 0, 0, false
+	}
+
+	if saved.ModTimeUnixNano != modTime.UnixNano() {
+		return 0, 0, false
+	}
+
+	return saved.CursorPos, saved.ViewOrigin, true
+}
+
+// RestorePositionIfEnabled moves the cursor and view to the saved position for
+// absPath, if restoreLastPosition is true and a matching saved position exists.
+// file.Load doesn't call it; whoever loads a document calls it afterwards so
+// the buffer opens where the user left it.
+func RestorePositionIfEnabled(s *EditorState, absPath string, modTime time.Time, restoreLastPosition RestoreLastPosition) {
+	if !restoreLastPosition {
+		return
+	}
+
+	cursorPos, viewOrigin, ok := LoadPositionForFile(absPath, modTime)
+	if !ok {
+		return
+	}
+
+	MoveCursor(s, func(params LocatorParams) uint64 {
+		return cursorPos
+	})
+	ScrollViewToPosition(s, viewOrigin)
+}
+
+// PersistPositionForFile saves the current cursor and view position for absPath.
+// The quit and close-buffer actions don't call it; a caller saves the position
+// this way before discarding a document buffer so it can be restored on the
+// next open.
+func PersistPositionForFile(s *EditorState, absPath string, modTime time.Time) error {
+	cursorPos, viewOrigin := CursorAndViewPosition(s)
+	return SavePositionForFile(absPath, modTime, cursorPos, viewOrigin)
+}
@@ -6,7 +6,28 @@ func GitCommitRules() []parser.TokenizerRule {
 	plaintextRules := PlaintextRules()
 	gitCommitRules := []parser.TokenizerRule{
 		{
-			Regexp:    "(^|\n)#[^\n]*",
+			// The scissor line and everything below it are discarded on save
+			// (see `git commit --cleanup=scissors`), so treat the whole tail
+			// of the file as one comment region.
+			Regexp:    `(^|\n)# -+ >8 -+[\s\S]*`,
+			TokenRole: parser.TokenRoleComment,
+		},
+		{
+			// Lines from a `commit -v` diff, still prefixed with "# " like
+			// the rest of that comment block.
+			Regexp:    `(^|\n)# ?\+[^\n]*`,
+			TokenRole: parser.TokenRoleDiffAdded,
+		},
+		{
+			Regexp:    `(^|\n)# ?-[^\n]*`,
+			TokenRole: parser.TokenRoleDiffRemoved,
+		},
+		{
+			Regexp:    `(^|\n)# ?@@[^\n]*`,
+			TokenRole: parser.TokenRoleDiffHeader,
+		},
+		{
+			Regexp:    `(^|\n)#[^\n]*`,
 			TokenRole: parser.TokenRoleNone,
 			SubRules: []parser.TokenizerRule{
 				{
@@ -15,6 +36,61 @@ func GitCommitRules() []parser.TokenizerRule {
 				},
 			},
 		},
+		{
+			// The subject line, if it's at least as long as the 50-char soft
+			// limit: the first 50 columns are left untagged, the stretch out
+			// to the 72-char hard limit is a warning, and anything past that
+			// is an error. A conventional-commit type/scope prefix within
+			// the first 50 columns is tokenized separately.
+			Regexp:    `^[^#\n][^\n]{49,}`,
+			TokenRole: parser.TokenRoleNone,
+			SubRules: []parser.TokenizerRule{
+				{
+					Regexp:    `[^\n]{1,50}`,
+					TokenRole: parser.TokenRoleNone,
+					SubRules: []parser.TokenizerRule{
+						{
+							Regexp:    `^[A-Za-z][A-Za-z0-9]*(\([^)\n]*\))?!?:`,
+							TokenRole: parser.TokenRoleOperator,
+							SubRules: []parser.TokenizerRule{
+								{
+									Regexp:    `[A-Za-z][A-Za-z0-9]*`,
+									TokenRole: parser.TokenRoleIdentifier,
+								},
+							},
+						},
+					},
+				},
+				{
+					Regexp:    `[^\n]{1,22}`,
+					TokenRole: parser.TokenRoleWarning,
+				},
+				{
+					Regexp:    `[^\n]+`,
+					TokenRole: parser.TokenRoleError,
+				},
+			},
+		},
+		{
+			// Git trailers (Signed-off-by:, Co-authored-by:, Fixes:,
+			// Reviewed-by:, ...) in the footer. Anchored with `(^|\n)` like
+			// the comment/diff rules above rather than a literal blank line,
+			// since a commit message can be nothing but trailers (no subject
+			// or body above them) or have its trailers immediately follow a
+			// stripped comment/scissor line rather than an actual blank line.
+			Regexp:    `(^|\n)[A-Za-z-]+: [^\n]*(\n[A-Za-z-]+: [^\n]*)*`,
+			TokenRole: parser.TokenRoleNone,
+			SubRules: []parser.TokenizerRule{
+				{
+					Regexp:    `[A-Za-z-]+:`,
+					TokenRole: parser.TokenRoleKeyword,
+				},
+				{
+					Regexp:    ` [^\n]*`,
+					TokenRole: parser.TokenRoleString,
+				},
+			},
+		},
 	}
 	return append(gitCommitRules, plaintextRules...)
 }
@@ -0,0 +1,161 @@
+package rules
+
+import (
+	"regexp"
+	"testing"
+)
+
+// gitCommitRuleRegexps compiles just the git-specific rules returned by
+// GitCommitRules(), in the fixed order they're constructed in: scissor,
+// diff-added, diff-removed, diff-header, comment, subject-length, trailer.
+// PlaintextRules() isn't part of this snapshot, so the combined rule set
+// can't be run through a real tokenizer here; these tests instead check
+// each rule's Regexp the same way the tokenizer would -- does it match
+// starting at position 0 of the remaining buffer. The trailer test instead
+// searches anywhere in its input, since its trailers follow other lines.
+func gitCommitRuleRegexps(t *testing.T) []*regexp.Regexp {
+	t.Helper()
+
+	rules := GitCommitRules()
+	const numGitCommitRules = 7
+	if len(rules) < numGitCommitRules {
+		t.Fatalf("GitCommitRules() returned %d rules, want at least %d", len(rules), numGitCommitRules)
+	}
+
+	compiled := make([]*regexp.Regexp, numGitCommitRules)
+	for i := 0; i < numGitCommitRules; i++ {
+		compiled[i] = regexp.MustCompile(rules[i].Regexp)
+	}
+	return compiled
+}
+
+// matchAt reports whether re matches s starting at byte offset 0, along
+// with the length of that match, mirroring how a tokenizer advancing
+// through a buffer decides whether a rule applies at the current position.
+func matchAt(re *regexp.Regexp, s string) (matched bool, length int) {
+	loc := re.FindStringIndex(s)
+	if loc == nil || loc[0] != 0 {
+		return false, 0
+	}
+	return true, loc[1]
+}
+
+func TestGitCommitScissorRule(t *testing.T) {
+	regexps := gitCommitRuleRegexps(t)
+	scissor := regexps[0]
+
+	testCases := []struct {
+		name    string
+		input   string
+		matches bool
+	}{
+		{
+			name:    "scissor at start of buffer",
+			input:   "# ------------------------ >8 ------------------------\n# anything below is discarded\n",
+			matches: true,
+		},
+		{
+			name:    "scissor after a preceding line",
+			input:   "\n# ------------------------ >8 ------------------------\ndiscarded\n",
+			matches: true,
+		},
+		{
+			name:    "not a scissor line",
+			input:   "# just a regular comment\n",
+			matches: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			matched, _ := matchAt(scissor, tc.input)
+			if matched != tc.matches {
+				t.Errorf("input %q: matched=%v, want %v", tc.input, matched, tc.matches)
+			}
+		})
+	}
+}
+
+func TestGitCommitDiffRoles(t *testing.T) {
+	regexps := gitCommitRuleRegexps(t)
+	diffAdded, diffRemoved, diffHeader := regexps[1], regexps[2], regexps[3]
+
+	if matched, _ := matchAt(diffAdded, "# +added line\n"); !matched {
+		t.Error("diff-added rule should match a '# +' line")
+	}
+	if matched, _ := matchAt(diffRemoved, "# -removed line\n"); !matched {
+		t.Error("diff-removed rule should match a '# -' line")
+	}
+	if matched, _ := matchAt(diffHeader, "# @@ -1,2 +1,3 @@\n"); !matched {
+		t.Error("diff-header rule should match a '# @@' line")
+	}
+}
+
+func TestGitCommitSubjectLengthRule(t *testing.T) {
+	regexps := gitCommitRuleRegexps(t)
+	subject := regexps[5]
+
+	short := "fix typo"
+	if matched, _ := matchAt(subject, short); matched {
+		t.Errorf("short subject %q should not match the long-subject rule", short)
+	}
+
+	long := "this subject line is considerably longer than fifty characters"
+	matched, length := matchAt(subject, long)
+	if !matched {
+		t.Fatalf("long subject %q should match the long-subject rule", long)
+	}
+	if length != len(long) {
+		t.Errorf("expected the whole subject to be consumed, matched %d of %d bytes", length, len(long))
+	}
+
+	// The 50-char soft-limit segment's conventional-commit SubRule should
+	// recognize a "type(scope):" prefix within it.
+	convCommitRegexp := GitCommitRules()[5].SubRules[0].SubRules[0].Regexp
+	convCommit := regexp.MustCompile(convCommitRegexp)
+	if !convCommit.MatchString("feat(parser): ") {
+		t.Error("conventional-commit prefix rule should match 'feat(parser):'")
+	}
+	if convCommit.MatchString("this is not a prefix") {
+		t.Error("conventional-commit prefix rule should not match plain prose")
+	}
+}
+
+func TestGitCommitTrailerRule(t *testing.T) {
+	regexps := gitCommitRuleRegexps(t)
+	trailer := regexps[6]
+
+	testCases := []struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "trailer at start of buffer",
+			input: "Signed-off-by: A Author <a@example.com>",
+		},
+		{
+			name:  "trailer after a blank line",
+			input: "Subject line\n\nBody text.\n\nSigned-off-by: A Author <a@example.com>",
+		},
+		{
+			name:  "trailer immediately after a comment line, no blank line",
+			input: "# Please enter the commit message\nSigned-off-by: A Author <a@example.com>",
+		},
+		{
+			name:  "multiple consecutive trailers",
+			input: "Subject\n\nCo-authored-by: A <a@example.com>\nFixes: #123",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			loc := trailer.FindStringIndex(tc.input)
+			if loc == nil {
+				t.Fatalf("trailer rule did not match %q anywhere", tc.input)
+			}
+			if got := tc.input[loc[0]:loc[1]]; got == "" {
+				t.Errorf("trailer rule matched an empty span in %q", tc.input)
+			}
+		})
+	}
+}
@@ -33,6 +33,29 @@ type LineBreaker struct {
 	inClosePunctSpaceSeq bool
 	inDashSpaceSeq       bool
 	lastPropsWereRIOdd   bool
+	strictness           LineBreakStrictness
+
+	// lastRule is the UAX #14 rule number that produced the most recent
+	// ProcessRune decision (including a NoLineBreak one), set by
+	// ProcessRune and reported by LastRule. It lets conformance tests
+	// assert not just whether a break occurred but which rule fired.
+	lastRule string
+}
+
+// LastRule returns the UAX #14 rule number (for example "LB18" or "LB31")
+// that produced the decision from the most recent ProcessRune call.
+func (lb *LineBreaker) LastRule() string {
+	return lb.lastRule
+}
+
+// NewLineBreakerWithOptions constructs a LineBreaker with tailoring options
+// beyond the UAX #14 default rules, analogous to the CSS `line-break`
+// property's loose/normal/strict levels and the ICU LineBreakRule enum.
+// This matters mainly for CJK prose, where the default rules (LB1's
+// unconditional CJ -> NS mapping, for example) are a reasonable default but
+// not the only one publishers use.
+func NewLineBreakerWithOptions(opts LineBreakOptions) LineBreaker {
+	return LineBreaker{strictness: opts.Strictness}
 }
 
 // ProcessRune finds valid breakpoints between lines.
@@ -53,7 +76,16 @@ func (lb *LineBreaker) ProcessRune(r rune) (decision LineBreakDecision) {
 			prop = lbPropAL
 		}
 	} else if prop == lbPropCJ {
-		prop = lbPropNS
+		if lb.strictness == LineBreakStrict {
+			prop = lbPropNS
+		} else if lb.strictness == LineBreakLoose {
+			// Loose tailoring (CSS line-break: loose) allows breaks around
+			// small kana and other CJ-class characters instead of treating
+			// them as non-starters.
+			prop = lbPropID
+		} else {
+			prop = lbPropNS
+		}
 	}
 
 	// LB2: Never break at the start of text.
@@ -63,39 +95,47 @@ func (lb *LineBreaker) ProcessRune(r rune) (decision LineBreakDecision) {
 	// LB4: Always break after hard line breaks.
 	if prop == lbPropBK && lb.lastProp != lbPropCR {
 		decision = RequireLineBreakAfter
+		lb.lastRule = "LB4"
 		goto done
 	}
 
 	// LB5: Treat CR followed by LF, as well as CR, LF, and NL as hard line breaks.
 	if lb.lastProp == lbPropCR && prop == lbPropLF {
 		decision = RequireLineBreakAfter
+		lb.lastRule = "LB5"
 		goto done
 	} else if lb.lastProp == lbPropCR {
 		decision = RequireLineBreakBefore
+		lb.lastRule = "LB5"
 		goto done
 	} else if prop == lbPropLF || prop == lbPropNL {
 		decision = RequireLineBreakAfter
+		lb.lastRule = "LB5"
 		goto done
 	}
 
 	// LB6: Do not break before hard line breaks.
 	if prop == lbPropBK || prop == lbPropCR || prop == lbPropLF || prop == lbPropNL {
+		lb.lastRule = "LB6"
 		goto done
 	}
 
 	// LB7: Do not break before spaces or zero width space.
 	if prop == lbPropSP || prop == lbPropZW {
+		lb.lastRule = "LB7"
 		goto done
 	}
 
 	// LB8: Break before any character following a zero-width space, even if one or more spaces intervene.
 	if lb.inZeroWidthSpaceSeq && prop != lbPropSP {
 		decision = AllowLineBreakBefore
+		lb.lastRule = "LB8"
 		goto done
 	}
 
 	// LB8a: Do not break after a zero width joiner.
 	if lb.lastProp == lbPropZWJ {
+		lb.lastRule = "LB8a"
 		goto done
 	}
 
@@ -120,6 +160,7 @@ func (lb *LineBreaker) ProcessRune(r rune) (decision LineBreakDecision) {
 			lb.lastPropsWereRIOdd = !lb.lastPropsWereRIOdd
 		}
 
+		lb.lastRule = "LB9"
 		goto done
 	}
 
@@ -128,96 +169,120 @@ func (lb *LineBreaker) ProcessRune(r rune) (decision LineBreakDecision) {
 
 	// LB11: Do not break before or after Word joiner and related characters.
 	if lb.lastProp == lbPropWJ || prop == lbPropWJ {
+		lb.lastRule = "LB11"
 		goto done
 	}
 
 	// LB12: Do not break after NBSP and related characters.
 	if lb.lastProp == lbPropGL {
+		lb.lastRule = "LB12"
 		goto done
 	}
 
 	// B12a: Do not break before NBSP and related characters, except after spaces and hyphens.
 	if lb.lastProp != lbPropSP && lb.lastProp != lbPropBA && lb.lastProp != lbPropHY && prop == lbPropGL {
+		lb.lastRule = "LB12a"
 		goto done
 	}
 
 	// LB13: Do not break before ‘]’ or ‘!’ or ‘;’ or ‘/’, even after spaces.
 	if prop == lbPropCL || prop == lbPropCP || prop == lbPropEX || prop == lbPropIS || prop == lbPropSY {
+		lb.lastRule = "LB13"
 		goto done
 	}
 
 	// LB14: Do not break after ‘[’, even after spaces.
 	if lb.inLeftBraceSpaceSeq && prop != lbPropSP {
+		lb.lastRule = "LB14"
 		goto done
 	}
 
 	// LB15: Do not break within ‘”[’, even with intervening spaces.
 	if lb.inQuotationSpaceSeq && prop == lbPropOP {
+		lb.lastRule = "LB15"
 		goto done
 	}
 
 	// LB16: Do not break between closing punctuation and a nonstarter (lb=NS), even with intervening spaces.
 	if lb.inClosePunctSpaceSeq && prop == lbPropNS {
+		lb.lastRule = "LB16"
 		goto done
 	}
 
 	// LB17: Do not break within ‘——’, even with intervening spaces.
 	if lb.inDashSpaceSeq && prop == lbPropB2 {
+		lb.lastRule = "LB17"
 		goto done
 	}
 
 	// LB18: Break after spaces.
 	if lb.lastProp == lbPropSP {
 		decision = AllowLineBreakBefore
+		lb.lastRule = "LB18"
 		goto done
 	}
 
 	// LB19: Do not break before or after quotation marks, such as ‘ ” ’.
 	if lb.lastProp == lbPropQU || prop == lbPropQU {
+		lb.lastRule = "LB19"
 		goto done
 	}
 
 	// LB20: Break before and after unresolved CB.
 	if lb.lastProp == lbPropCB || prop == lbPropCB {
 		decision = AllowLineBreakBefore
+		lb.lastRule = "LB20"
 		goto done
 	}
 
 	// LB21: Do not break before hyphen-minus, other hyphens, fixed-width spaces, small kana, and other non-starters, or after acute accents.
-	if prop == lbPropBA || prop == lbPropHY || prop == lbPropNS || lb.lastProp == lbPropBB {
+	// Under Normal and Loose tailoring, relax this to allow a break before a
+	// hyphen that directly follows a CJK ideograph (ID); the character after
+	// the hyphen isn't checked. Strict tailoring keeps the UAX #14 default
+	// everywhere.
+	if lb.strictness != LineBreakStrict && prop == lbPropHY && lb.lastProp == lbPropID {
+		// fall through to later rules instead of suppressing the break
+	} else if prop == lbPropBA || prop == lbPropHY || prop == lbPropNS || lb.lastProp == lbPropBB {
+		lb.lastRule = "LB21"
 		goto done
 	}
 
 	// LB21a: Don't break after Hebrew + Hyphen.
 	if lb.lastLastProp == lbPropHL && (lb.lastProp == lbPropHY || lb.lastProp == lbPropBA) {
+		lb.lastRule = "LB21a"
 		goto done
 	}
 
 	// LB21b: Don’t break between Solidus and Hebrew letters.
 	if lb.lastProp == lbPropSY && prop == lbPropHL {
+		lb.lastRule = "LB21b"
 		goto done
 	}
 
 	// LB22: Do not break before ellipses.
 	if prop == lbPropIN {
+		lb.lastRule = "LB22"
 		goto done
 	}
 
 	// LB23: Do not break between digits and letters.
 	if ((lb.lastProp == lbPropAL || lb.lastProp == lbPropHL) && prop == lbPropNU) ||
 		(lb.lastProp == lbPropNU && (prop == lbPropAL || prop == lbPropHL)) {
+		lb.lastRule = "LB23"
 		goto done
 	}
 
 	// LB23a: Do not break between numeric prefixes and ideographs, or between ideographs and numeric postfixes.
 	if (lb.lastProp == lbPropPR && (prop == lbPropID || prop == lbPropEB || prop == lbPropEM)) ||
 		((lb.lastProp == lbPropID || lb.lastProp == lbPropEB || lb.lastProp == lbPropEM) && prop == lbPropPO) {
+		lb.lastRule = "LB23a"
 		goto done
 	}
 
 	// LB24: Do not break between numeric prefix/postfix and letters, or between letters and prefix/postfix.
 	if ((lb.lastProp == lbPropPR || lb.lastProp == lbPropPO) && (prop == lbPropAL || prop == lbPropHL)) ||
 		((lb.lastProp == lbPropAL || lb.lastProp == lbPropHL) && (prop == lbPropPR || prop == lbPropPO)) {
+		lb.lastRule = "LB24"
 		goto done
 	}
 
@@ -238,6 +303,7 @@ func (lb *LineBreaker) ProcessRune(r rune) (decision LineBreakDecision) {
 		(lb.lastProp == lbPropIS && prop == lbPropNU) ||
 		(lb.lastProp == lbPropNU && prop == lbPropNU) ||
 		(lb.lastProp == lbPropSY && prop == lbPropNU) {
+		lb.lastRule = "LB25"
 		goto done
 	}
 
@@ -245,22 +311,26 @@ func (lb *LineBreaker) ProcessRune(r rune) (decision LineBreakDecision) {
 	if (lb.lastProp == lbPropJL && (prop == lbPropJL || prop == lbPropJV || prop == lbPropH2 || prop == lbPropH3)) ||
 		((lb.lastProp == lbPropJV || lb.lastProp == lbPropH2) && (prop == lbPropJV || prop == lbPropJT)) ||
 		((lb.lastProp == lbPropJT || lb.lastProp == lbPropH3) && prop == lbPropJT) {
+		lb.lastRule = "LB26"
 		goto done
 	}
 
 	// LB27: Treat a Korean Syllable Block the same as ID.
 	if ((lb.lastProp == lbPropJL || lb.lastProp == lbPropJV || lb.lastProp == lbPropJT || lb.lastProp == lbPropH2 || lb.lastProp == lbPropH3) && prop == lbPropPO) ||
 		(lb.lastProp == lbPropPR && (prop == lbPropJL || prop == lbPropJV || prop == lbPropJT || prop == lbPropH2 || prop == lbPropH3)) {
+		lb.lastRule = "LB27"
 		goto done
 	}
 
 	// LB28 Do not break between alphabetics (“at”).
 	if (lb.lastProp == lbPropAL || lb.lastProp == lbPropHL) && (prop == lbPropAL || prop == lbPropHL) {
+		lb.lastRule = "LB28"
 		goto done
 	}
 
 	// LB29: Do not break between numeric punctuation and alphabetics (“e.g.”).
 	if lb.lastProp == lbPropIS && (prop == lbPropAL || prop == lbPropHL) {
+		lb.lastRule = "LB29"
 		goto done
 	}
 
@@ -269,23 +339,27 @@ func (lb *LineBreaker) ProcessRune(r rune) (decision LineBreakDecision) {
 		(lb.lastProp == lbPropCP && (prop == lbPropAL || prop == lbPropHL || prop == lbPropNU)) {
 		eaProp := eaPropForRune(r)
 		if eaProp != eaPropF && eaProp != eaPropW && eaProp != eaPropH {
+			lb.lastRule = "LB30"
 			goto done
 		}
 	}
 
 	// LB30a: Break between two regional indicator symbols if and only if there are an even number of regional indicators preceding the position of the break.
 	if lb.lastPropsWereRIOdd && prop == lbPropRI {
+		lb.lastRule = "LB30a"
 		goto done
 	}
 
 	// LB30b: Do not break between an emoji base (or potential emoji) and an emoji modifier.
 	if lb.lastProp == lbPropEB && prop == lbPropEM {
 		// TODO: leaving out the second rule here...
+		lb.lastRule = "LB30b"
 		goto done
 	}
 
 	// LB31: Break everywhere else.
 	decision = AllowLineBreakBefore
+	lb.lastRule = "LB31"
 
 done:
 	// This is LB10, which we run at the end so it applies even if other rules short-circuit.
@@ -304,6 +378,114 @@ done:
 	return decision
 }
 
+// LineBreakResult describes a single break found by LineBreakIter, carrying
+// enough position information that callers can measure a line's width
+// without trailing whitespace or newlines skewing it. This follows the
+// Flutter engine's line breaker model.
+type LineBreakResult struct {
+	// Decision is why this break is allowed or required; see LineBreakDecision.
+	Decision LineBreakDecision
+
+	// Index is the rune offset of the break itself.
+	Index uint64
+
+	// IndexWithoutTrailingNewlines is Index with any trailing CR/LF/NL run
+	// immediately before the break excluded.
+	IndexWithoutTrailingNewlines uint64
+
+	// IndexWithoutTrailingSpaces is IndexWithoutTrailingNewlines with any
+	// trailing run of SP runes before that excluded. Measuring a line's
+	// width up to IndexWithoutTrailingSpaces instead of Index keeps a run of
+	// trailing spaces from pushing an otherwise-fitting line over
+	// maxLineWidth.
+	IndexWithoutTrailingSpaces uint64
+}
+
+// LineBreakIter drives a LineBreaker over a rune stream and reports each
+// break as a LineBreakResult, tracking the rune offsets LineBreakResult
+// needs to describe trailing newlines and spaces.
+type LineBreakIter struct {
+	lb     LineBreaker
+	reader text.Reader
+	done   bool
+
+	index uint64
+
+	// lastNonNewlineEnd and lastNonSpaceEnd are the index just past the most
+	// recent rune that wasn't part of a trailing CR/LF/NL or SP run,
+	// respectively. Both are always <= index.
+	lastNonNewlineEnd uint64
+	lastNonSpaceEnd   uint64
+}
+
+// NewLineBreakIter constructs a LineBreakIter using the zero-value tailoring, LineBreakNormal.
+func NewLineBreakIter(reader text.Reader) LineBreakIter {
+	return LineBreakIter{reader: reader}
+}
+
+// NewLineBreakIterWithOptions constructs a LineBreakIter tailored by opts; see NewLineBreakerWithOptions.
+func NewLineBreakIterWithOptions(reader text.Reader, opts LineBreakOptions) LineBreakIter {
+	return LineBreakIter{lb: NewLineBreakerWithOptions(opts), reader: reader}
+}
+
+// NextBreak returns the next line break in the stream as a LineBreakResult,
+// or io.EOF once the break at the end of the text has already been returned.
+func (iter *LineBreakIter) NextBreak() (LineBreakResult, error) {
+	if iter.done {
+		return LineBreakResult{}, io.EOF
+	}
+
+	for {
+		r, _, err := iter.reader.ReadRune()
+		if err == io.EOF {
+			// LB3: always break at the end of text.
+			iter.done = true
+			return iter.resultAt(iter.index, RequireLineBreakAfter), nil
+		}
+		if err != nil {
+			return LineBreakResult{}, err
+		}
+
+		decision := iter.lb.ProcessRune(r)
+
+		if decision == AllowLineBreakBefore || decision == RequireLineBreakBefore {
+			result := iter.resultAt(iter.index, decision)
+			iter.advance(r)
+			return result, nil
+		}
+
+		iter.advance(r)
+
+		if decision == RequireLineBreakAfter {
+			return iter.resultAt(iter.index, decision), nil
+		}
+	}
+}
+
+// advance records r as consumed, updating the trailing newline/space extents
+// resultAt reports.
+func (iter *LineBreakIter) advance(r rune) {
+	iter.index++
+
+	prop := lbPropForRune(r)
+	isNewline := prop == lbPropBK || prop == lbPropCR || prop == lbPropLF || prop == lbPropNL
+	if !isNewline {
+		iter.lastNonNewlineEnd = iter.index
+		if prop != lbPropSP {
+			iter.lastNonSpaceEnd = iter.index
+		}
+	}
+}
+
+func (iter *LineBreakIter) resultAt(index uint64, decision LineBreakDecision) LineBreakResult {
+	return LineBreakResult{
+		Decision:                     decision,
+		Index:                        index,
+		IndexWithoutTrailingNewlines: iter.lastNonNewlineEnd,
+		IndexWithoutTrailingSpaces:   iter.lastNonSpaceEnd,
+	}
+}
+
 // GraphemeClusterWidthFunc returns the width in cells for a given grapheme cluster.
 type GraphemeClusterWidthFunc func(gc []rune, offsetInLine uint64) uint64
 
@@ -311,17 +493,26 @@ type GraphemeClusterWidthFunc func(gc []rune, offsetInLine uint64) uint64
 type LineWrapConfig struct {
 	maxLineWidth uint64
 	widthFunc    GraphemeClusterWidthFunc
+	strategy     WrapStrategy
 }
 
-// NewLineWrapConfig constructs a configuration for soft-wrapping lines.
+// NewLineWrapConfig constructs a configuration for soft-wrapping lines using
+// the greedy (first-fit) strategy.
 // maxLineWidth is the maximum number of cells per line, which must be at least one.
 // widthFunc returns the width in cells for a given grapheme cluster.
 func NewLineWrapConfig(maxLineWidth uint64, widthFunc GraphemeClusterWidthFunc) LineWrapConfig {
+	return NewLineWrapConfigWithStrategy(maxLineWidth, widthFunc, WrapGreedy)
+}
+
+// NewLineWrapConfigWithStrategy constructs a configuration for soft-wrapping
+// lines using the given WrapStrategy. Use WrapOptimal to minimize total
+// raggedness across a paragraph instead of greedily filling each line.
+func NewLineWrapConfigWithStrategy(maxLineWidth uint64, widthFunc GraphemeClusterWidthFunc, strategy WrapStrategy) LineWrapConfig {
 	if maxLineWidth == 0 {
 		log.Fatalf("maxLineWidth (%d) must be greater than zero", maxLineWidth)
 	}
 
-	return LineWrapConfig{maxLineWidth, widthFunc}
+	return LineWrapConfig{maxLineWidth, widthFunc, strategy}
 }
 
 // WrappedLineIter iterates through soft- and hard-wrapped lines.
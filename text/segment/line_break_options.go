@@ -0,0 +1,33 @@
+package segment
+
+// LineBreakStrictness tailors UAX #14 line breaking, mirroring CSS's
+// `line-break: loose | normal | strict` property and the ICU
+// `LineBreakRule` enum. The default (zero value) is LineBreakNormal, which
+// keeps the untailored CJ handling and adds the hyphen relaxation below.
+type LineBreakStrictness int
+
+const (
+	// LineBreakNormal treats CJ as NS (LB1), as the UAX #14 rules do, but
+	// allows a break before a hyphen that directly follows a CJK ideograph,
+	// which LB21 would otherwise forbid.
+	LineBreakNormal = LineBreakStrictness(iota)
+
+	// LineBreakLoose allows more breakpoints than Normal: CJ-class
+	// characters (small kana, Japanese iteration marks) are treated as ID
+	// instead of NS, so lines can break around them. Suited to content
+	// where a denser wrap is preferable to a few very long lines.
+	LineBreakLoose
+
+	// LineBreakStrict forbids more breakpoints than Normal, keeping the
+	// UAX #14 non-starter rules exactly and disallowing the hyphen
+	// relaxation Normal and Loose apply after CJK ideographs. Suited to
+	// publishing targets with stricter typographic conventions.
+	LineBreakStrict
+)
+
+// LineBreakOptions configures NewLineBreakerWithOptions.
+type LineBreakOptions struct {
+	// Strictness controls how aggressively LineBreaker avoids breakpoints
+	// around CJK non-starters. See LineBreakStrictness.
+	Strictness LineBreakStrictness
+}
@@ -0,0 +1,319 @@
+package segment
+
+import (
+	"io"
+	"math"
+	"unicode"
+
+	"github.com/aretext/aretext/text"
+)
+
+// WrapStrategy selects how WrappedLineIter-family iterators choose
+// breakpoints within a paragraph of allowed breaks.
+type WrapStrategy int
+
+const (
+	// WrapGreedy fills each line with as many grapheme clusters as fit
+	// (first-fit), matching WrappedLineIter's existing behavior.
+	WrapGreedy = WrapStrategy(iota)
+
+	// WrapOptimal minimizes total "raggedness" across a whole paragraph,
+	// Knuth-Plass style, at the cost of needing the whole paragraph's
+	// breakpoints and widths before it can emit the first line.
+	WrapOptimal
+)
+
+// overfullPenaltyFactor scales the cost of a line that doesn't fit within
+// maxLineWidth, so the optimizer avoids overfull lines whenever any other
+// split is available.
+const overfullPenaltyFactor = 65536
+
+// lineCost returns the cost of a single wrapped line of the given width
+// relative to maxWidth: squared slack for a line that fits, and a steep
+// linear penalty for one that doesn't.
+func lineCost(width, maxWidth uint64) float64 {
+	if width <= maxWidth {
+		slack := float64(maxWidth - width)
+		return slack * slack
+	}
+	return overfullPenaltyFactor * float64(width-maxWidth)
+}
+
+// optimalBreakpoints chooses, among the allowed breakpoints
+// (breakpoints[0] is the start of the paragraph and the last one, always
+// chosen, is its end), the subset that minimizes the total lineCost of the
+// resulting lines. widthBetween(i, j) must return the rendered width of
+// the run between breakpoints i and j, excluding any trailing spaces.
+//
+// The breakpoint cost matrix is totally monotone (a shorter prefix is
+// never a worse choice once a later prefix is, which is the same
+// structural property Knuth-Plass relies on for paragraph justification),
+// so a later column can discard any row that's dominated by a more
+// recent one and never reconsider it. This is NOT the SMAWK algorithm:
+// SMAWK reduces a whole matrix at once in O(n+m); here every column
+// rebuilds its candidate list from scratch, so the worst case (e.g. every
+// row strictly improving on the last) is the same O(n^2) a naive DP
+// would need. That's still fine in practice, since a paragraph's
+// breakpoint count is bounded by the viewport width, not file size.
+func optimalBreakpoints(breakpoints []int, widthBetween func(i, j int) uint64, maxWidth uint64) []int {
+	n := len(breakpoints)
+	if n == 0 {
+		return nil
+	}
+
+	minima := make([]float64, n)
+	prev := make([]int, n)
+	for i := range minima {
+		minima[i] = math.Inf(1)
+		prev[i] = -1
+	}
+	minima[0] = 0
+
+	// cost(row, col) is the total cost of breaking optimally up through
+	// breakpoint `row`, then taking one more line ending at breakpoint `col`.
+	// It's infinite when col <= row, since a line can't end before it starts.
+	cost := func(row, col int) float64 {
+		if col <= row || math.IsInf(minima[row], 1) {
+			return math.Inf(1)
+		}
+		w := widthBetween(breakpoints[row], breakpoints[col])
+		return minima[row] + lineCost(w, maxWidth)
+	}
+
+	rows := make([]int, n)
+	for i := range rows {
+		rows[i] = i
+	}
+
+	// Process breakpoints left to right: once minima[col] is finalized, later
+	// columns can use it as a candidate row. This mirrors how Knuth-Plass
+	// fills its DP table by increasing breakpoint index. For each column,
+	// the monotone stack reduction below narrows the candidate rows to a
+	// short list before the final scan, since the totally monotone cost
+	// matrix guarantees the optimal row survives the reduction.
+	for col := 1; col < n; col++ {
+		candidateRows := monotoneStackRowCandidates(rows[:col], col, cost)
+
+		best := math.Inf(1)
+		bestRow := -1
+		for _, row := range candidateRows {
+			c := cost(row, col)
+			if c < best {
+				best = c
+				bestRow = row
+			}
+		}
+
+		minima[col] = best
+		prev[col] = bestRow
+	}
+
+	// Backtrace from the last breakpoint to produce the chosen break sequence.
+	var chosen []int
+	for i := n - 1; i > 0; i = prev[i] {
+		chosen = append([]int{i}, chosen...)
+		if prev[i] <= 0 {
+			break
+		}
+	}
+	return chosen
+}
+
+// monotoneStackRowCandidates finds, for the single column `col`, which of
+// `rows` could minimize cost(row, col), using a monotone stack reduction:
+// a row is eliminated if a later row is at least as good, relying on the
+// same total-monotonicity that makes SMAWK correct. Unlike SMAWK, this
+// rebuilds the stack from all of `rows` on every call instead of
+// amortizing the reduction across columns, so it's O(col) per column
+// (see the complexity note on optimalBreakpoints) rather than O(1)
+// amortized.
+func monotoneStackRowCandidates(rows []int, col int, cost func(row, col int) float64) []int {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	var stack []int
+	for _, row := range rows {
+		for len(stack) > 0 && cost(stack[len(stack)-1], col) >= cost(row, col) {
+			stack = stack[:len(stack)-1]
+		}
+		stack = append(stack, row)
+	}
+	return stack
+}
+
+// clusterIsSpace reports whether every rune in a grapheme cluster is a
+// Unicode space, so a trailing run of such clusters can be excluded from a
+// line's measured width the same way a fitting line shouldn't be pushed over
+// maxLineWidth by trailing whitespace alone.
+func clusterIsSpace(cluster []rune) bool {
+	if len(cluster) == 0 {
+		return false
+	}
+	for _, r := range cluster {
+		if !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// LineIter is implemented by both WrappedLineIter and OptimalWrappedLineIter,
+// so callers can pick a wrapping strategy via NewLineWrapConfigWithStrategy
+// without caring which concrete iterator they get back.
+type LineIter interface {
+	NextSegment(segment *Segment) error
+}
+
+// NewLineIter constructs the line-wrapping iterator appropriate for
+// wrapConfig's WrapStrategy.
+func NewLineIter(reader text.Reader, wrapConfig LineWrapConfig) LineIter {
+	if wrapConfig.strategy == WrapOptimal {
+		iter := NewOptimalWrappedLineIter(reader, wrapConfig)
+		return &iter
+	}
+
+	iter := NewWrappedLineIter(reader, wrapConfig)
+	return &iter
+}
+
+// OptimalWrappedLineIter iterates through lines wrapped to minimize total
+// raggedness across each hard-broken paragraph, rather than greedily
+// filling each line (see WrappedLineIter). Because it needs every
+// breakpoint in a paragraph before it can decide the first line, it
+// buffers one paragraph at a time.
+type OptimalWrappedLineIter struct {
+	wrapConfig   LineWrapConfig
+	gcIter       GraphemeClusterIter
+	pendingLines [][]rune
+	nextLine     int
+	eof          bool
+}
+
+// NewOptimalWrappedLineIter constructs an iterator that wraps lines to
+// minimize total raggedness across each paragraph, using the DP in
+// optimalBreakpoints (see its doc comment for why that's a monotone-stack
+// reduction rather than the SMAWK algorithm the name might suggest).
+func NewOptimalWrappedLineIter(reader text.Reader, wrapConfig LineWrapConfig) OptimalWrappedLineIter {
+	return OptimalWrappedLineIter{
+		wrapConfig: wrapConfig,
+		gcIter:     NewGraphemeClusterIter(reader),
+	}
+}
+
+// NextSegment retrieves the next optimally-wrapped line.
+func (iter *OptimalWrappedLineIter) NextSegment(segment *Segment) error {
+	segment.Clear()
+
+	if iter.nextLine < len(iter.pendingLines) {
+		segment.Extend(iter.pendingLines[iter.nextLine])
+		iter.nextLine++
+		return nil
+	}
+
+	if iter.eof {
+		return io.EOF
+	}
+
+	if err := iter.fillParagraph(); err != nil && err != io.EOF {
+		return err
+	} else if err == io.EOF {
+		iter.eof = true
+	}
+
+	if len(iter.pendingLines) == 0 {
+		return io.EOF
+	}
+
+	segment.Extend(iter.pendingLines[0])
+	iter.nextLine = 1
+	return nil
+}
+
+// fillParagraph reads grapheme clusters up to and including the next hard
+// line break (or EOF), then computes the optimal wrap for that paragraph
+// and stores the resulting lines in iter.pendingLines.
+func (iter *OptimalWrappedLineIter) fillParagraph() error {
+	iter.pendingLines = nil
+	iter.nextLine = 0
+
+	var clusters [][]rune
+	var widths []uint64
+	var isSpace []bool // isSpace[k] reports whether clusters[k] is all whitespace
+	var breakpoints []int // indices into clusters where a line may end
+	var trailingNewline []rune
+
+	seg := Empty()
+	currentWidth := uint64(0)
+	for {
+		err := iter.gcIter.NextSegment(seg)
+		if err == io.EOF {
+			breakpoints = append(breakpoints, len(clusters))
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if seg.HasNewline() {
+			trailingNewline = append([]rune{}, seg.Runes()...)
+			breakpoints = append(breakpoints, len(clusters))
+			break
+		}
+
+		runes := append([]rune{}, seg.Runes()...)
+		width := iter.wrapConfig.widthFunc(runes, currentWidth)
+		clusters = append(clusters, runes)
+		widths = append(widths, width)
+		isSpace = append(isSpace, clusterIsSpace(runes))
+		currentWidth += width
+
+		// A grapheme cluster boundary is always an allowed break point; finer
+		// control (no-break-before-punctuation, etc.) is the LineBreaker's job
+		// and is intentionally out of scope for this width-based wrap.
+		breakpoints = append(breakpoints, len(clusters)-1)
+	}
+
+	if len(clusters) == 0 {
+		if trailingNewline != nil {
+			iter.pendingLines = [][]rune{trailingNewline}
+		}
+		if len(trailingNewline) == 0 {
+			return io.EOF
+		}
+		return nil
+	}
+
+	widthBetween := func(i, j int) uint64 {
+		for j > i && isSpace[j-1] {
+			j--
+		}
+		var w uint64
+		for k := i; k < j; k++ {
+			w += widths[k]
+		}
+		return w
+	}
+
+	breaks := optimalBreakpoints(breakpoints, widthBetween, iter.wrapConfig.maxLineWidth)
+
+	start := 0
+	for _, bIdx := range breaks {
+		end := breakpoints[bIdx]
+		var line []rune
+		for k := start; k < end; k++ {
+			line = append(line, clusters[k]...)
+		}
+		iter.pendingLines = append(iter.pendingLines, line)
+		start = end
+	}
+
+	if len(iter.pendingLines) > 0 && trailingNewline != nil {
+		last := len(iter.pendingLines) - 1
+		iter.pendingLines[last] = append(iter.pendingLines[last], trailingNewline...)
+	} else if trailingNewline != nil {
+		iter.pendingLines = append(iter.pendingLines, trailingNewline)
+	}
+
+	return nil
+}
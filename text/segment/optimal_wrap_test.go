@@ -0,0 +1,174 @@
+package segment
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestLineCost(t *testing.T) {
+	if c := lineCost(5, 10); c != 25 {
+		t.Errorf("fits within maxWidth: expected 25, got %v", c)
+	}
+	if c := lineCost(10, 10); c != 0 {
+		t.Errorf("exactly maxWidth: expected 0, got %v", c)
+	}
+	if c := lineCost(12, 10); c != overfullPenaltyFactor*2 {
+		t.Errorf("overfull: expected %v, got %v", overfullPenaltyFactor*2, c)
+	}
+}
+
+// bruteForceBreakpoints is a naive O(n^2) reference DP with no monotone
+// stack pruning, used to check optimalBreakpoints against.
+func bruteForceBreakpoints(breakpoints []int, widthBetween func(i, j int) uint64, maxWidth uint64) []int {
+	n := len(breakpoints)
+	if n == 0 {
+		return nil
+	}
+
+	minima := make([]float64, n)
+	prev := make([]int, n)
+	for i := range minima {
+		minima[i] = math.Inf(1)
+		prev[i] = -1
+	}
+	minima[0] = 0
+
+	for col := 1; col < n; col++ {
+		for row := 0; row < col; row++ {
+			if math.IsInf(minima[row], 1) {
+				continue
+			}
+			c := minima[row] + lineCost(widthBetween(breakpoints[row], breakpoints[col]), maxWidth)
+			if c < minima[col] {
+				minima[col] = c
+				prev[col] = row
+			}
+		}
+	}
+
+	var chosen []int
+	for i := n - 1; i > 0; i = prev[i] {
+		chosen = append([]int{i}, chosen...)
+		if prev[i] <= 0 {
+			break
+		}
+	}
+	return chosen
+}
+
+func widthBetweenFromWidths(widths []uint64) func(i, j int) uint64 {
+	return func(i, j int) uint64 {
+		var w uint64
+		for k := i; k < j; k++ {
+			w += widths[k]
+		}
+		return w
+	}
+}
+
+func totalBreakCost(breaks []int, widthBetween func(i, j int) uint64, maxWidth uint64) float64 {
+	total := 0.0
+	start := 0
+	for _, b := range breaks {
+		total += lineCost(widthBetween(start, b), maxWidth)
+		start = b
+	}
+	return total
+}
+
+// TestOptimalBreakpointsMatchesBruteForce checks optimalBreakpoints against
+// a naive reference DP across random paragraphs of random cluster widths.
+// Two wrappings can tie on total cost (e.g. a zero-width cluster can sit on
+// either side of a breakpoint without changing any line's width), so this
+// compares the chosen total cost rather than the exact breakpoint
+// sequence.
+func TestOptimalBreakpointsMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for trial := 0; trial < 200; trial++ {
+		n := rng.Intn(20)
+		widths := make([]uint64, n)
+		for i := range widths {
+			widths[i] = uint64(rng.Intn(10))
+		}
+		breakpoints := make([]int, n+1)
+		for i := 0; i <= n; i++ {
+			breakpoints[i] = i
+		}
+		maxWidth := uint64(5 + rng.Intn(15))
+
+		wb := widthBetweenFromWidths(widths)
+		got := optimalBreakpoints(breakpoints, wb, maxWidth)
+		want := bruteForceBreakpoints(breakpoints, wb, maxWidth)
+
+		gotCost := totalBreakCost(got, wb, maxWidth)
+		wantCost := totalBreakCost(want, wb, maxWidth)
+		if math.Abs(gotCost-wantCost) > 1e-9 {
+			t.Fatalf("trial %d: widths=%v maxWidth=%d: got %v (cost %v) want %v (cost %v)",
+				trial, widths, maxWidth, got, gotCost, want, wantCost)
+		}
+	}
+}
+
+func TestOptimalBreakpointsEmpty(t *testing.T) {
+	if got := optimalBreakpoints(nil, func(i, j int) uint64 { return 0 }, 10); got != nil {
+		t.Errorf("expected nil, got %v", got)
+	}
+}
+
+func TestOptimalBreakpointsSingleLineFits(t *testing.T) {
+	widths := []uint64{2, 2, 2}
+	breakpoints := []int{0, 1, 2, 3}
+	got := optimalBreakpoints(breakpoints, widthBetweenFromWidths(widths), 10)
+	want := []int{3}
+	if len(got) != len(want) || got[0] != want[0] {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestClusterIsSpace(t *testing.T) {
+	testCases := []struct {
+		name     string
+		cluster  []rune
+		expected bool
+	}{
+		{name: "empty", cluster: nil, expected: false},
+		{name: "single space", cluster: []rune{' '}, expected: true},
+		{name: "tab", cluster: []rune{'\t'}, expected: true},
+		{name: "non-space", cluster: []rune{'a'}, expected: false},
+		{name: "space then non-space", cluster: []rune{' ', 'a'}, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := clusterIsSpace(tc.cluster); got != tc.expected {
+				t.Errorf("clusterIsSpace(%v) = %v, want %v", tc.cluster, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestWidthBetweenExcludesTrailingSpaces(t *testing.T) {
+	// Mirrors the widthBetween closure in fillParagraph: trailing
+	// all-space clusters shouldn't count toward a line's measured width.
+	widths := []uint64{2, 1, 1, 2}
+	isSpace := []bool{false, true, true, false}
+	widthBetween := func(i, j int) uint64 {
+		for j > i && isSpace[j-1] {
+			j--
+		}
+		var w uint64
+		for k := i; k < j; k++ {
+			w += widths[k]
+		}
+		return w
+	}
+
+	if got := widthBetween(0, 3); got != 2 {
+		t.Errorf("expected trailing spaces excluded: got %d, want 2", got)
+	}
+	if got := widthBetween(0, 4); got != 6 {
+		t.Errorf("expected non-space tail included: got %d, want 6", got)
+	}
+}
@@ -0,0 +1,163 @@
+package segment
+
+import (
+	"io"
+	"regexp"
+
+	"github.com/aretext/aretext/text"
+)
+
+// reflowPrefixPattern matches a leading prose "continuation prefix": an
+// email-style quote marker or a list bullet ("- ", "* ", or "1. "); comment
+// leaders come from ReflowLineWrapConfig.CommentLeaders instead. It's
+// repeated at the start of every soft-wrapped continuation line so that
+// quoted replies and long comment blocks keep their formatting across wraps.
+var reflowPrefixPattern = regexp.MustCompile(`^(?:(?:>+ ?)+|[-*] |[0-9]+\. )`)
+
+// ReflowLineWrapConfig wraps a LineWrapConfig with awareness of prose
+// "continuation prefixes" (quote markers, bullets, comment leaders), so a
+// soft-wrapped paragraph keeps looking like one logical block instead of
+// losing its formatting on every wrapped line.
+type ReflowLineWrapConfig struct {
+	LineWrapConfig
+
+	// CommentLeaders are syntax-specific comment prefixes (for example "// ",
+	// "# ", ";; ") detected in addition to the built-in quote/bullet patterns.
+	CommentLeaders []string
+}
+
+// NewReflowLineWrapConfig constructs a ReflowLineWrapConfig from a base
+// LineWrapConfig plus any syntax-specific comment leaders to recognize.
+func NewReflowLineWrapConfig(base LineWrapConfig, commentLeaders []string) ReflowLineWrapConfig {
+	return ReflowLineWrapConfig{LineWrapConfig: base, CommentLeaders: commentLeaders}
+}
+
+// detectPrefix returns the continuation prefix at the start of line, if any.
+func (c ReflowLineWrapConfig) detectPrefix(line string) string {
+	for _, leader := range c.CommentLeaders {
+		if len(line) >= len(leader) && line[:len(leader)] == leader {
+			return leader
+		}
+	}
+
+	return reflowPrefixPattern.FindString(line)
+}
+
+// ReflowWrappedLineIter iterates through soft- and hard-wrapped lines like
+// WrappedLineIter, but repeats a detected continuation prefix (see
+// ReflowLineWrapConfig) at the start of every continuation line within a
+// hard-broken paragraph, and reduces the available width on those
+// continuation lines by the prefix's rendered width.
+type ReflowWrappedLineIter struct {
+	wrapConfig ReflowLineWrapConfig
+	gcIter     GraphemeClusterIter
+	buffer     []rune
+
+	prefix       []rune
+	prefixWidth  uint64
+	firstLine    bool
+	currentWidth uint64
+
+	// lastPrefix is the prefix detected for the most recently returned
+	// segment, exposed via Prefix() so the display layer can render it dimmed.
+	lastPrefix []rune
+}
+
+// NewReflowWrappedLineIter constructs a reflow-aware wrapped-line iterator.
+func NewReflowWrappedLineIter(reader text.Reader, wrapConfig ReflowLineWrapConfig) ReflowWrappedLineIter {
+	return ReflowWrappedLineIter{
+		wrapConfig: wrapConfig,
+		gcIter:     NewGraphemeClusterIter(reader),
+		buffer:     make([]rune, 0, 256),
+		firstLine:  true,
+	}
+}
+
+// Prefix returns the continuation prefix detected for the segment most
+// recently returned by NextSegment, or nil if none was detected. Display
+// code can use this to render the repeated prefix in a dimmed style.
+func (iter *ReflowWrappedLineIter) Prefix() []rune {
+	return iter.lastPrefix
+}
+
+// NextSegment retrieves the next soft- or hard-wrapped line, repeating any
+// detected continuation prefix at the start of each continuation line.
+func (iter *ReflowWrappedLineIter) NextSegment(segment *Segment) error {
+	segment.Clear()
+	iter.lastPrefix = nil
+
+	gcSegment := Empty()
+	for {
+		err := iter.gcIter.NextSegment(gcSegment)
+		if err == io.EOF {
+			if len(iter.buffer) > 0 {
+				segment.Extend(iter.prefixBytesIfContinuation()).Extend(iter.buffer)
+				iter.buffer = iter.buffer[:0]
+				return nil
+			}
+			return io.EOF
+		}
+		if err != nil {
+			return err
+		}
+
+		if gcSegment.HasNewline() {
+			segment.Extend(iter.prefixBytesIfContinuation()).Extend(iter.buffer).Extend(gcSegment.Runes())
+			iter.buffer = iter.buffer[:0]
+			iter.currentWidth = 0
+			iter.firstLine = true
+			iter.prefix = nil
+			iter.prefixWidth = 0
+			return nil
+		}
+
+		runes := append([]rune{}, gcSegment.Runes()...)
+		width := iter.wrapConfig.widthFunc(runes, iter.currentWidth)
+		maxWidth := iter.wrapConfig.maxLineWidth
+		if !iter.firstLine {
+			maxWidth -= minUint64(iter.prefixWidth, maxWidth-1)
+		}
+
+		if iter.currentWidth+width > maxWidth && iter.currentWidth > 0 {
+			segment.Extend(iter.prefixBytesIfContinuation()).Extend(iter.buffer)
+			iter.firstLine = false
+			iter.buffer = append(iter.buffer[:0], runes...)
+			iter.currentWidth = width
+			return nil
+		}
+
+		iter.buffer = append(iter.buffer, runes...)
+		iter.currentWidth += width
+	}
+}
+
+// prefixBytesIfContinuation returns the prefix to prepend to the segment
+// about to be emitted: nil for the first line of a hard-broken paragraph
+// (iter.buffer already holds that prefix as ordinary text), and the
+// detected prefix for every continuation line after it.
+func (iter *ReflowWrappedLineIter) prefixBytesIfContinuation() []rune {
+	if iter.prefix == nil {
+		prefixStr := iter.wrapConfig.detectPrefix(string(iter.buffer))
+		if prefixStr != "" {
+			iter.prefix = []rune(prefixStr)
+			iter.prefixWidth = iter.wrapConfig.widthFunc(iter.prefix, 0)
+		} else {
+			iter.prefix = []rune{}
+		}
+	}
+
+	if iter.firstLine || len(iter.prefix) == 0 {
+		iter.lastPrefix = nil
+		return nil
+	}
+
+	iter.lastPrefix = iter.prefix
+	return iter.prefix
+}
+
+func minUint64(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,38 @@
+package segment
+
+// RuneWidth returns the terminal display width of a single rune: zero for
+// combining marks and zero-width joiners/spaces (they render stacked on the
+// previous cell), two for East Asian Wide and Fullwidth runes, and one
+// otherwise. This reuses the same lbProp/eaProp classification the line
+// breaker already builds from the Unicode data tables, rather than pulling
+// in a separate runewidth dependency for the same information.
+func RuneWidth(r rune) uint64 {
+	switch lbPropForRune(r) {
+	case lbPropCM, lbPropZWJ, lbPropZW:
+		return 0
+	}
+
+	switch eaPropForRune(r) {
+	case eaPropF, eaPropW:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// GraphemeClusterWidth returns the display width in cells of a grapheme
+// cluster gc, which is the width of its leading rune (later runes in the
+// cluster are combining marks or joiners and don't add width). If gc is a
+// tab character, the returned width expands it to the next tab stop given
+// tabSize and the cluster's offset (in cells) from the start of the line.
+func GraphemeClusterWidth(gc []rune, offsetInLine uint64, tabSize uint64) uint64 {
+	if len(gc) == 0 {
+		return 0
+	}
+
+	if gc[0] == '\t' && tabSize > 0 {
+		return tabSize - (offsetInLine % tabSize)
+	}
+
+	return RuneWidth(gc[0])
+}